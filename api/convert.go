@@ -3,10 +3,12 @@ package api
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"github.com/danesparza/fxpixel/internal/data"
 	"github.com/danesparza/fxpixel/internal/data/const/effect"
 	"github.com/danesparza/fxpixel/internal/data/const/step"
-	"github.com/rs/zerolog/log"
+	"math/rand"
+	"reflect"
 	"time"
 )
 
@@ -21,17 +23,27 @@ func TimelineToApi(tl data.Timeline) Timeline {
 		Name:    tl.Name,
 		GPIO:    int(tl.GPIO.Int32),
 		Tags:    tl.Tags,
+		Filters: filtersToApi(tl.Filters),
 	}
 
 	//	For each step ...
 	for _, item := range tl.Steps {
 		newStep := TimelineStep{
-			ID:     item.ID,
-			Type:   item.Type.String(),
-			Effect: item.Effect.String(),
-			Leds:   item.Leds.String,
-			Time:   int(item.Time.Int32),
-			Number: item.Number,
+			ID:      item.ID,
+			Type:    item.Type.String(),
+			Effect:  item.Effect.String(),
+			Leds:    item.Leds.String,
+			Time:    int(item.Time.Int32),
+			Number:  item.Number,
+			Filters: filtersToApi(item.Filters),
+		}
+
+		if item.Transition != nil {
+			newStep.Transition = &StepTransition{
+				Type:     item.Transition.Type,
+				Duration: item.Transition.Duration,
+				Easing:   item.Transition.Easing,
+			}
 		}
 
 		//	... determine the step type
@@ -49,33 +61,98 @@ func TimelineToApi(tl data.Timeline) Timeline {
 						G: md.Color.G,
 						B: md.Color.B,
 						W: md.Color.W,
+						K: md.Color.K,
 					},
 				}
 			case effect.Fade:
 				md := item.MetaInfo.(data.FadeMeta)
+				//	Copy the start color
+				var fromColor *MetaColor
+				if md.FromColor != nil {
+					fromColor = &MetaColor{
+						R: md.FromColor.R,
+						G: md.FromColor.G,
+						B: md.FromColor.B,
+						W: md.FromColor.W,
+						K: md.FromColor.K,
+					}
+				}
+				//	Copy the sequence
+				var sequence []MetaColor
+				for _, item := range md.Sequence {
+					sequence = append(sequence, MetaColor{
+						R: item.R,
+						G: item.G,
+						B: item.B,
+						W: item.W,
+						K: item.K,
+					})
+				}
+				//	Copy the stops
+				var stops []ColorStop
+				for _, item := range md.Stops {
+					stops = append(stops, ColorStop{
+						Position: item.Position,
+						Color: MetaColor{
+							R: item.Color.R,
+							G: item.Color.G,
+							B: item.Color.B,
+							W: item.Color.W,
+							K: item.Color.K,
+						},
+					})
+				}
 				newStep.MetaInfo = FadeMeta{
 					Color: MetaColor{
 						R: md.Color.R,
 						G: md.Color.G,
 						B: md.Color.B,
 						W: md.Color.W,
+						K: md.Color.K,
 					},
+					Easing:     md.Easing,
+					FromColor:  fromColor,
+					From:       md.From,
+					Sequence:   sequence,
+					Stops:      stops,
+					Palette:    md.Palette,
+					ColorSpace: md.ColorSpace,
 				}
 			case effect.Gradient:
 				md := item.MetaInfo.(data.GradientMeta)
+				//	Copy the stops
+				var stops []ColorStop
+				for _, item := range md.Stops {
+					stops = append(stops, ColorStop{
+						Position: item.Position,
+						Color: MetaColor{
+							R: item.Color.R,
+							G: item.Color.G,
+							B: item.Color.B,
+							W: item.Color.W,
+							K: item.Color.K,
+						},
+					})
+				}
 				newStep.MetaInfo = GradientMeta{
 					StartColor: MetaColor{
 						R: md.StartColor.R,
 						G: md.StartColor.G,
 						B: md.StartColor.B,
 						W: md.StartColor.W,
+						K: md.StartColor.K,
 					},
 					EndColor: MetaColor{
 						R: md.EndColor.R,
 						G: md.EndColor.G,
 						B: md.EndColor.B,
 						W: md.EndColor.W,
+						K: md.EndColor.K,
 					},
+					Stops:      stops,
+					Palette:    md.Palette,
+					ColorSpace: md.ColorSpace,
+					Speed:      md.Speed,
 				}
 			case effect.Sequence:
 				md := item.MetaInfo.(data.SequenceMeta)
@@ -87,6 +164,7 @@ func TimelineToApi(tl data.Timeline) Timeline {
 						G: item.G,
 						B: item.B,
 						W: item.W,
+						K: item.K,
 					}
 					sequenceSlice = append(sequenceSlice, sequenceItem)
 				}
@@ -101,7 +179,9 @@ func TimelineToApi(tl data.Timeline) Timeline {
 						G: md.Color.G,
 						B: md.Color.B,
 						W: md.Color.W,
+						K: md.Color.K,
 					},
+					Easing: md.Easing,
 				}
 			case effect.KnightRider:
 				//	Don't need to do anything
@@ -113,11 +193,311 @@ func TimelineToApi(tl data.Timeline) Timeline {
 					BurstSpacing:    md.BurstSpacing,
 					BurstLength:     md.BurstLength,
 					BurstBrightness: md.BurstBrightness,
+					StrikeSize:      md.StrikeSize,
+					StrikeFalloff:   md.StrikeFalloff,
+				}
+			case effect.Plasma, effect.Noise:
+				md := item.MetaInfo.(data.NoiseMeta)
+				//	Copy the palette colors
+				colorSlice := []MetaColor{}
+				for _, item := range md.Colors {
+					colorItem := MetaColor{
+						R: item.R,
+						G: item.G,
+						B: item.B,
+						W: item.W,
+						K: item.K,
+					}
+					colorSlice = append(colorSlice, colorItem)
+				}
+				newStep.MetaInfo = NoiseMeta{
+					Scale:   md.Scale,
+					Speed:   md.Speed,
+					Octaves: md.Octaves,
+					Colors:  colorSlice,
+					Palette: md.Palette,
+				}
+			case effect.Candle:
+				md := item.MetaInfo.(data.CandleMeta)
+				newStep.MetaInfo = CandleMeta{
+					Temperature: md.Temperature,
+					Brightness:  md.Brightness,
+					Intensity:   md.Intensity,
+					Wind:        md.Wind,
+					GroupSize:   md.GroupSize,
+				}
+			case effect.BouncingBalls:
+				md := item.MetaInfo.(data.BouncingBallsMeta)
+				//	Copy the ball colors
+				colorSlice := []MetaColor{}
+				for _, item := range md.Colors {
+					colorItem := MetaColor{
+						R: item.R,
+						G: item.G,
+						B: item.B,
+						W: item.W,
+						K: item.K,
+					}
+					colorSlice = append(colorSlice, colorItem)
+				}
+				newStep.MetaInfo = BouncingBallsMeta{
+					Balls:     md.Balls,
+					Colors:    colorSlice,
+					Gravity:   md.Gravity,
+					Dampening: md.Dampening,
+					Relaunch:  md.Relaunch,
+				}
+			case effect.Strobe:
+				md := item.MetaInfo.(data.StrobeMeta)
+				newStep.MetaInfo = StrobeMeta{
+					Color: MetaColor{
+						R: md.Color.R,
+						G: md.Color.G,
+						B: md.Color.B,
+						W: md.Color.W,
+						K: md.Color.K,
+					},
+					Frequency: md.Frequency,
+					DutyCycle: md.DutyCycle,
+					Flashes:   md.Flashes,
+				}
+			case effect.Beacon:
+				md := item.MetaInfo.(data.BeaconMeta)
+				//	Copy the segments
+				segmentSlice := []BeaconSegment{}
+				for _, item := range md.Segments {
+					colorSlice := []MetaColor{}
+					for _, color := range item.Colors {
+						colorSlice = append(colorSlice, MetaColor{
+							R: color.R,
+							G: color.G,
+							B: color.B,
+							W: color.W,
+							K: color.K,
+						})
+					}
+					segmentSlice = append(segmentSlice, BeaconSegment{
+						Leds:    item.Leds,
+						Colors:  colorSlice,
+						Flashes: item.Flashes,
+						Offset:  item.Offset,
+						Rotate:  item.Rotate,
+					})
+				}
+				newStep.MetaInfo = BeaconMeta{
+					Preset:   md.Preset,
+					Period:   md.Period,
+					Segments: segmentSlice,
+				}
+			case effect.Image:
+				md := item.MetaInfo.(data.ImageMeta)
+				newStep.MetaInfo = ImageMeta{
+					Image:   md.Image,
+					Rate:    md.Rate,
+					Loops:   md.Loops,
+					Reverse: md.Reverse,
+				}
+			case effect.Gif:
+				md := item.MetaInfo.(data.GifMeta)
+				newStep.MetaInfo = GifMeta{
+					Image: md.Image,
+					Loops: md.Loops,
+					Scale: md.Scale,
+				}
+			case effect.Text:
+				md := item.MetaInfo.(data.TextMeta)
+				//	Copy the colors
+				colorSlice := []MetaColor{}
+				for _, item := range md.Colors {
+					colorItem := MetaColor{
+						R: item.R,
+						G: item.G,
+						B: item.B,
+						W: item.W,
+						K: item.K,
+					}
+					colorSlice = append(colorSlice, colorItem)
+				}
+				newStep.MetaInfo = TextMeta{
+					Text: md.Text,
+					Font: md.Font,
+					Color: MetaColor{
+						R: md.Color.R,
+						G: md.Color.G,
+						B: md.Color.B,
+						W: md.Color.W,
+						K: md.Color.K,
+					},
+					Colors:    colorSlice,
+					Palette:   md.Palette,
+					Speed:     md.Speed,
+					Direction: md.Direction,
+					Repeat:    md.Repeat,
+				}
+			case effect.Clock:
+				md := item.MetaInfo.(data.ClockMeta)
+				newStep.MetaInfo = ClockMeta{
+					HourColor: MetaColor{
+						R: md.HourColor.R,
+						G: md.HourColor.G,
+						B: md.HourColor.B,
+						W: md.HourColor.W,
+						K: md.HourColor.K,
+					},
+					MinuteColor: MetaColor{
+						R: md.MinuteColor.R,
+						G: md.MinuteColor.G,
+						B: md.MinuteColor.B,
+						W: md.MinuteColor.W,
+						K: md.MinuteColor.K,
+					},
+					SecondColor: MetaColor{
+						R: md.SecondColor.R,
+						G: md.SecondColor.G,
+						B: md.SecondColor.B,
+						W: md.SecondColor.W,
+						K: md.SecondColor.K,
+					},
+					MarkerColor: MetaColor{
+						R: md.MarkerColor.R,
+						G: md.MarkerColor.G,
+						B: md.MarkerColor.B,
+						W: md.MarkerColor.W,
+						K: md.MarkerColor.K,
+					},
+					Format:   md.Format,
+					Font:     md.Font,
+					Timezone: md.Timezone,
+					Offset:   md.Offset,
+					Reverse:  md.Reverse,
+				}
+			case effect.Countdown:
+				md := item.MetaInfo.(data.CountdownMeta)
+				newStep.MetaInfo = CountdownMeta{
+					Target:   md.Target,
+					Duration: md.Duration,
+					Color: MetaColor{
+						R: md.Color.R,
+						G: md.Color.G,
+						B: md.Color.B,
+						W: md.Color.W,
+						K: md.Color.K,
+					},
+					Font:    md.Font,
+					Reverse: md.Reverse,
+				}
+			case effect.VUMeter:
+				md := item.MetaInfo.(data.VUMeterMeta)
+				//	Copy the colors
+				colorSlice := []MetaColor{}
+				for _, item := range md.Colors {
+					colorItem := MetaColor{
+						R: item.R,
+						G: item.G,
+						B: item.B,
+						W: item.W,
+						K: item.K,
+					}
+					colorSlice = append(colorSlice, colorItem)
+				}
+				newStep.MetaInfo = VUMeterMeta{
+					Colors:  colorSlice,
+					Palette: md.Palette,
+					PeakColor: MetaColor{
+						R: md.PeakColor.R,
+						G: md.PeakColor.G,
+						B: md.PeakColor.B,
+						W: md.PeakColor.W,
+						K: md.PeakColor.K,
+					},
+					Mirror:      md.Mirror,
+					Sensitivity: md.Sensitivity,
+				}
+			case effect.Spectrum:
+				md := item.MetaInfo.(data.SpectrumMeta)
+				//	Copy the colors
+				colorSlice := []MetaColor{}
+				for _, item := range md.Colors {
+					colorItem := MetaColor{
+						R: item.R,
+						G: item.G,
+						B: item.B,
+						W: item.W,
+						K: item.K,
+					}
+					colorSlice = append(colorSlice, colorItem)
+				}
+				newStep.MetaInfo = SpectrumMeta{
+					Colors:      colorSlice,
+					Palette:     md.Palette,
+					Sensitivity: md.Sensitivity,
+				}
+			case effect.BeatPulse:
+				md := item.MetaInfo.(data.BeatPulseMeta)
+				//	Copy the colors
+				colorSlice := []MetaColor{}
+				for _, item := range md.Colors {
+					colorItem := MetaColor{
+						R: item.R,
+						G: item.G,
+						B: item.B,
+						W: item.W,
+						K: item.K,
+					}
+					colorSlice = append(colorSlice, colorItem)
+				}
+				newStep.MetaInfo = BeatPulseMeta{
+					Colors:  colorSlice,
+					Palette: md.Palette,
+					Decay:   md.Decay,
+				}
+			case effect.Formula:
+				md := item.MetaInfo.(data.FormulaMeta)
+				newStep.MetaInfo = FormulaMeta{
+					Formula: md.Formula,
+				}
+			case effect.Script:
+				md := item.MetaInfo.(data.ScriptMeta)
+				newStep.MetaInfo = ScriptMeta{
+					Script: md.Script,
+					Params: md.Params,
+				}
+			case effect.Sunrise, effect.Sunset:
+				md := item.MetaInfo.(data.SunriseMeta)
+				newStep.MetaInfo = SunriseMeta{
+					NightTemperature: md.NightTemperature,
+					DayTemperature:   md.DayTemperature,
+					Brightness:       md.Brightness,
+				}
+			case effect.Circadian:
+				md := item.MetaInfo.(data.CircadianMeta)
+				//	Copy the keyframes
+				var keyframes []CircadianKeyframe
+				for _, item := range md.Keyframes {
+					keyframes = append(keyframes, CircadianKeyframe{
+						Time:        item.Time,
+						Temperature: item.Temperature,
+						Brightness:  item.Brightness,
+					})
+				}
+				newStep.MetaInfo = CircadianMeta{
+					Keyframes: keyframes,
 				}
 			}
 		case step.Sleep:
 		case step.RandomSleep:
 		case step.Loop:
+			md := item.MetaInfo.(data.LoopMeta)
+			newStep.MetaInfo = LoopMeta{
+				Count: md.Count,
+				Step:  md.Step,
+				Label: md.Label,
+			}
+		case step.Label:
+			md := item.MetaInfo.(data.LabelMeta)
+			newStep.MetaInfo = LabelMeta{
+				Name: md.Name,
+			}
 		case step.Trigger:
 			md := item.MetaInfo.(data.TriggerMeta)
 			newStep.MetaInfo = TriggerMeta{
@@ -129,6 +509,11 @@ func TimelineToApi(tl data.Timeline) Timeline {
 		default:
 		}
 
+		//	Put the random values back where they were set
+		if len(item.Random) > 0 {
+			newStep.MetaInfo = inlineRandom(newStep.MetaInfo, item.Random)
+		}
+
 		//	Then add the step to the list of steps:
 		retval.Steps = append(retval.Steps, newStep)
 	}
@@ -137,8 +522,10 @@ func TimelineToApi(tl data.Timeline) Timeline {
 	return retval
 }
 
-// ApiToTimeline converts api format to internal data model
-func ApiToTimeline(tl Timeline) data.Timeline {
+// ApiToTimeline converts api format to internal data model.  It returns an error
+// if a step's meta info can't be read (an invalid color, for example) or a loop
+// doesn't go back to an earlier step
+func ApiToTimeline(tl Timeline) (data.Timeline, error) {
 
 	//	Convert the base timeline information
 	retval := data.Timeline{
@@ -147,77 +534,275 @@ func ApiToTimeline(tl Timeline) data.Timeline {
 		Name:    tl.Name,
 		GPIO:    sql.NullInt32{Int32: int32(tl.GPIO), Valid: true},
 		Tags:    tl.Tags,
+		Filters: apiToFilters(tl.Filters),
+	}
+
+	if err := data.CheckFilters(retval.Filters); err != nil {
+		return retval, fmt.Errorf("problem with the timeline filters: %v", err)
 	}
 
 	//	For each step ...
 	for _, item := range tl.Steps {
 		newStep := data.TimelineStep{
-			ID:     item.ID,
-			Type:   step.FromString(item.Type),
-			Effect: effect.FromString(item.Effect),
-			Leds:   sql.NullString{String: item.Leds, Valid: true},
-			Time:   sql.NullInt32{Int32: int32(item.Time), Valid: true},
-			Number: item.Number,
+			ID:      item.ID,
+			Type:    step.FromString(item.Type),
+			Effect:  effect.FromString(item.Effect),
+			Leds:    sql.NullString{String: item.Leds, Valid: true},
+			Time:    sql.NullInt32{Int32: int32(item.Time), Valid: true},
+			Number:  item.Number,
+			Filters: apiToFilters(item.Filters),
 		}
 
-		//	Convert the meta info to a json string:
-		metaInfo, _ := json.Marshal(item.MetaInfo)
-		jsonString := string(metaInfo)
+		if err := data.CheckFilters(newStep.Filters); err != nil {
+			return retval, fmt.Errorf("problem with the filters for step %v: %v", item.Number, err)
+		}
 
-		//	... determine the step type
-		switch newStep.Type {
-		case step.Effect:
-			/* If it's an effect, load effect meta */
-			switch newStep.Effect {
-			case effect.Unknown:
-				//	We don't know what to do here
-			case effect.Solid:
-				em := data.SolidMeta{}
-				err := json.Unmarshal([]byte(jsonString), &em)
-				if err != nil {
-					log.Err(err).Msg("Problem unmarshalling SolidMeta")
-				}
+		if item.Transition != nil {
+			newStep.Transition = &data.StepTransition{
+				Type:     item.Transition.Type,
+				Duration: item.Transition.Duration,
+				Easing:   item.Transition.Easing,
+			}
+			if err := data.CheckTransition(*newStep.Transition); err != nil {
+				return retval, fmt.Errorf("problem with the transition for step %v: %v", item.Number, err)
+			}
+		}
 
-				newStep.MetaInfo = em
-			case effect.Fade:
-				em := data.FadeMeta{}
-				json.Unmarshal([]byte(jsonString), &em)
-				newStep.MetaInfo = em
-			case effect.Gradient:
-				em := data.GradientMeta{}
-				json.Unmarshal([]byte(jsonString), &em)
-				newStep.MetaInfo = em
-			case effect.Sequence:
-				em := data.SequenceMeta{}
-				json.Unmarshal([]byte(jsonString), &em)
-				newStep.MetaInfo = em
-			case effect.Rainbow:
-				//	Don't need to do anything
-			case effect.Zip:
-				em := data.ZipMeta{}
-				json.Unmarshal([]byte(jsonString), &em)
-				newStep.MetaInfo = em
-			case effect.KnightRider:
-				//	Don't need to do anything
-			case effect.Lightning:
-				em := data.LightningMeta{}
-				json.Unmarshal([]byte(jsonString), &em)
-				newStep.MetaInfo = em
+		//	Pull out the random values (they're picked when the step plays).  Only typed
+		//	meta values can be random, so free-form ones (like script params) are left alone
+		meta := newMeta(newStep.Type, newStep.Effect)
+		random := map[string]data.RandomValue{}
+		metaInfo, err := extractRandom(item.MetaInfo, reflect.TypeOf(meta), "", random)
+		if err != nil {
+			return retval, fmt.Errorf("problem with a random value for step %v: %v", item.Number, err)
+		}
+
+		//	Convert the meta info to the type the step uses
+		if meta != nil {
+			jsonMeta, _ := json.Marshal(metaInfo)
+			err = json.Unmarshal(jsonMeta, meta)
+			newStep.MetaInfo = reflect.ValueOf(meta).Elem().Interface()
+		}
+
+		if err != nil {
+			return retval, fmt.Errorf("problem with the meta-info for step %v: %v", item.Number, err)
+		}
+
+		//	Make sure the random values fit the meta info
+		if len(random) > 0 {
+			newStep.Random = random
+			if err := data.CheckRandom(newStep.MetaInfo, random); err != nil {
+				return retval, fmt.Errorf("problem with the meta-info for step %v: %v", item.Number, err)
 			}
-		case step.Sleep:
-		case step.RandomSleep:
-		case step.Loop:
-		case step.Trigger:
-			em := data.TriggerMeta{}
-			json.Unmarshal([]byte(jsonString), &em)
-			newStep.MetaInfo = em
-		default:
+		}
+
+		//	Make sure the meta info makes sense (once its random values are picked)
+		resolved, _ := data.ResolveRandom(newStep.MetaInfo, random, rand.New(rand.NewSource(1)), nil)
+		if err := data.CheckMeta(resolved); err != nil {
+			return retval, fmt.Errorf("problem with the meta-info for step %v: %v", item.Number, err)
 		}
 
 		//	Then add the step to the list of steps:
 		retval.Steps = append(retval.Steps, newStep)
 	}
 
+	//	Make sure the loops go somewhere sensible
+	if err := data.CheckLoops(retval.Steps); err != nil {
+		return retval, fmt.Errorf("problem with a loop: %v", err)
+	}
+
 	//	Return the timeline
+	return retval, nil
+}
+
+// filtersToApi converts filters to api format
+func filtersToApi(filters []data.StepFilter) []StepFilter {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	retval := []StepFilter{}
+	for _, f := range filters {
+		filter := StepFilter{
+			Type:   f.Type,
+			Amount: f.Amount,
+			Speed:  f.Speed,
+		}
+		if f.Color != nil {
+			filter.Color = &MetaColor{R: f.Color.R, G: f.Color.G, B: f.Color.B, W: f.Color.W, K: f.Color.K}
+		}
+		retval = append(retval, filter)
+	}
+
+	return retval
+}
+
+// apiToFilters converts api format filters to the internal data model
+func apiToFilters(filters []StepFilter) []data.StepFilter {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	retval := []data.StepFilter{}
+	for _, f := range filters {
+		filter := data.StepFilter{
+			Type:   f.Type,
+			Amount: f.Amount,
+			Speed:  f.Speed,
+		}
+		if f.Color != nil {
+			filter.Color = &data.MetaColor{R: f.Color.R, G: f.Color.G, B: f.Color.B, W: f.Color.W, K: f.Color.K}
+		}
+		retval = append(retval, filter)
+	}
+
+	return retval
+}
+
+// PaletteToApi converts internal data model to api format
+func PaletteToApi(p data.Palette) Palette {
+
+	retval := Palette{
+		ID:      p.ID,
+		Name:    p.Name,
+		Created: p.Created.Format(time.RFC3339),
+		Stops:   []ColorStop{},
+	}
+
+	//	Copy the stops
+	for _, item := range p.Stops {
+		retval.Stops = append(retval.Stops, ColorStop{
+			Position: item.Position,
+			Color: MetaColor{
+				R: item.Color.R,
+				G: item.Color.G,
+				B: item.Color.B,
+				W: item.Color.W,
+				K: item.Color.K,
+			},
+		})
+	}
+
+	return retval
+}
+
+// ApiToPalette converts api format to internal data model
+func ApiToPalette(p Palette) data.Palette {
+
+	retval := data.Palette{
+		ID:    p.ID,
+		Name:  p.Name,
+		Stops: []data.ColorStop{},
+	}
+
+	//	Copy the stops
+	for _, item := range p.Stops {
+		retval.Stops = append(retval.Stops, data.ColorStop{
+			Position: item.Position,
+			Color: data.MetaColor{
+				R: item.Color.R,
+				G: item.Color.G,
+				B: item.Color.B,
+				W: item.Color.W,
+				K: item.Color.K,
+			},
+		})
+	}
+
 	return retval
 }
+
+// ImageToApi converts internal data model to api format
+func ImageToApi(i data.Image) Image {
+	return Image{
+		ID:      i.ID,
+		Name:    i.Name,
+		Created: i.Created.Format(time.RFC3339),
+		Width:   i.Width,
+		Height:  i.Height,
+	}
+}
+
+// ScriptToApi converts a script store entry to api format
+func ScriptToApi(s data.ScriptInfo, source string) Script {
+	return Script{
+		Name:     s.Name,
+		Size:     s.Size,
+		Modified: s.Modified.Format(time.RFC3339),
+		Source:   source,
+	}
+}
+
+// newMeta returns a pointer to new meta info of the type the step uses, or nil if the
+// step doesn't use any
+func newMeta(stepType step.StepType, effectType effect.EffectType) any {
+	switch stepType {
+	case step.Effect:
+		/* If it's an effect, load effect meta */
+		switch effectType {
+		case effect.Unknown:
+			//	We don't know what to do here
+		case effect.Solid:
+			return &data.SolidMeta{}
+		case effect.Fade:
+			return &data.FadeMeta{}
+		case effect.Gradient:
+			return &data.GradientMeta{}
+		case effect.Sequence:
+			return &data.SequenceMeta{}
+		case effect.Rainbow:
+			//	Don't need to do anything
+		case effect.Zip:
+			return &data.ZipMeta{}
+		case effect.KnightRider:
+			//	Don't need to do anything
+		case effect.Lightning:
+			return &data.LightningMeta{}
+		case effect.Plasma, effect.Noise:
+			return &data.NoiseMeta{}
+		case effect.Candle:
+			return &data.CandleMeta{}
+		case effect.BouncingBalls:
+			return &data.BouncingBallsMeta{}
+		case effect.Strobe:
+			return &data.StrobeMeta{}
+		case effect.Beacon:
+			return &data.BeaconMeta{}
+		case effect.Image:
+			return &data.ImageMeta{}
+		case effect.Gif:
+			return &data.GifMeta{}
+		case effect.Text:
+			return &data.TextMeta{}
+		case effect.Clock:
+			return &data.ClockMeta{}
+		case effect.Countdown:
+			return &data.CountdownMeta{}
+		case effect.VUMeter:
+			return &data.VUMeterMeta{}
+		case effect.Spectrum:
+			return &data.SpectrumMeta{}
+		case effect.BeatPulse:
+			return &data.BeatPulseMeta{}
+		case effect.Formula:
+			return &data.FormulaMeta{}
+		case effect.Script:
+			return &data.ScriptMeta{}
+		case effect.Sunrise, effect.Sunset:
+			return &data.SunriseMeta{}
+		case effect.Circadian:
+			return &data.CircadianMeta{}
+		}
+	case step.Sleep:
+	case step.RandomSleep:
+	case step.Loop:
+		return &data.LoopMeta{}
+	case step.Label:
+		return &data.LabelMeta{}
+	case step.Trigger:
+		return &data.TriggerMeta{}
+	default:
+	}
+
+	return nil
+}
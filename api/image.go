@@ -0,0 +1,250 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/danesparza/fxpixel/internal/data"
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/xid"
+	"github.com/rs/zerolog/log"
+	"image"
+	_ "image/gif"  // Register the gif decoder
+	_ "image/jpeg" // Register the jpeg decoder
+	_ "image/png"  // Register the png decoder
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxImageUploadSize is the largest image (in bytes) we'll accept
+const maxImageUploadSize = 32 << 20
+
+// GetAllImages godoc
+// @Summary List all images in the image store
+// @Description List all images in the image store
+// @Tags image
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} api.SystemResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /images [get]
+func (service Service) GetAllImages(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get a list of images
+	dbImages, err := service.DB.GetAllImages(req.Context())
+	if err != nil {
+		err = fmt.Errorf("error getting a list of images: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	For each image, convert it to the API model:
+	retval := []Image{}
+	for _, img := range dbImages {
+		retval = append(retval, ImageToApi(img))
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("%v image(s)", len(retval)),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
+
+// AddImage godoc
+// @Summary Uploads an image to the image store
+// @Description Uploads an image (png, gif or jpeg) to the image store
+// @Tags image
+// @Accept  mpfd
+// @Produce  json
+// @Param file formData file true "The image to upload"
+// @Param name formData string false "The image name.  Defaults to the file name"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /images [put]
+func (service Service) AddImage(rw http.ResponseWriter, req *http.Request) {
+
+	//	Parse the form
+	req.Body = http.MaxBytesReader(rw, req.Body, maxImageUploadSize)
+	if err := req.ParseMultipartForm(maxImageUploadSize); err != nil {
+		err = fmt.Errorf("problem parsing image upload: %v", err)
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := req.FormFile("file")
+	if err != nil {
+		err = fmt.Errorf("an image upload requires a file: %v", err)
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	//	Make sure it's an image we can read
+	config, format, err := image.DecodeConfig(file)
+	if err != nil {
+		err = fmt.Errorf("problem reading image: %v", err)
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Default the name to the file name (without the extension)
+	name := strings.TrimSpace(req.FormValue("name"))
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
+	}
+
+	//	Save the file to the image store
+	id := xid.New().String()
+	fileName := fmt.Sprintf("%v.%v", id, format)
+	if err := saveUpload(file, filepath.Join(service.ImageDir, fileName)); err != nil {
+		err = fmt.Errorf("problem saving image: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Add the image
+	dbImage, err := service.DB.AddImage(req.Context(), data.Image{
+		ID:       id,
+		Name:     name,
+		FileName: fileName,
+		Width:    config.Width,
+		Height:   config.Height,
+	})
+	if err != nil {
+		//	Clean up the file we just saved
+		os.Remove(filepath.Join(service.ImageDir, fileName))
+
+		err = fmt.Errorf("error adding an image: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Convert the image to the API model:
+	retval := ImageToApi(dbImage)
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("Image added: %v", retval.ID),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
+
+// GetImage godoc
+// @Summary Gets a single image
+// @Description Gets information about a single image
+// @Tags image
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The image id to get"
+// @Success 200 {object} api.SystemResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /images/{id} [get]
+func (service Service) GetImage(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the id from the url
+	imageId := chi.URLParam(req, "id")
+
+	//	Get the image
+	dbImage, err := service.DB.GetImage(req.Context(), imageId)
+	if err != nil {
+		err = fmt.Errorf("error getting an image: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Convert the image to the API model:
+	retval := ImageToApi(dbImage)
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("Image fetched: %v", retval.ID),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
+
+// DeleteImage godoc
+// @Summary Deletes an image in the system
+// @Description Deletes an image from the image store
+// @Tags image
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The image id to delete"
+// @Success 200 {object} api.SystemResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /images/{id} [delete]
+func (service Service) DeleteImage(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the id from the url
+	imageId := chi.URLParam(req, "id")
+
+	//	Find the image, so we know which file to remove
+	dbImage, err := service.DB.GetImage(req.Context(), imageId)
+	if err != nil {
+		err = fmt.Errorf("error getting an image: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Delete the image
+	err = service.DB.DeleteImage(req.Context(), imageId)
+	if err != nil {
+		err = fmt.Errorf("error deleting an image: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Remove the file from the image store
+	if err := os.Remove(filepath.Join(service.ImageDir, dbImage.FileName)); err != nil {
+		log.Err(err).Str("file", dbImage.FileName).Msg("Problem removing image file")
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("Image deleted: %v", imageId),
+		Data:    imageId,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
+
+// saveUpload copies the (already partially read) upload to the passed path
+func saveUpload(file io.ReadSeeker, path string) error {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return err
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, file)
+	return err
+}
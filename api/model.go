@@ -1,9 +1,17 @@
 package api
 
+import (
+	"encoding/json"
+	"github.com/danesparza/fxpixel/internal/data"
+)
+
 // SystemConfig represents the system configuration information
 type SystemConfig struct {
-	GPIO int `json:"gpio"`
-	LEDs int `json:"leds"`
+	GPIO             int  `json:"gpio"`
+	LEDs             int  `json:"leds"`
+	MatrixWidth      int  `json:"matrix_width"`
+	MatrixHeight     int  `json:"matrix_height"`
+	MatrixSerpentine bool `json:"matrix_serpentine"`
 }
 
 // Timeline represents a series of event frames to be shown in order
@@ -15,24 +23,100 @@ type Timeline struct {
 	GPIO    int            `json:"gpio,omitempty"`    // The GPIO device to play the timeline on.  Optional.  If not set, uses the default
 	Steps   []TimelineStep `json:"steps"`             // Steps for the timeline
 	Tags    []string       `json:"tags,omitempty"`    // List of Tags to associate with this timeline
+	Filters []StepFilter   `json:"filters,omitempty"` // Filters for every effect in the timeline (after the step's own filters)
 }
 
 // TimelineStep represents a single step in a timeline
 type TimelineStep struct {
-	ID       string `json:"id"`                  // The timeline step id
-	Type     string `json:"type"`                // Timeline frame type (effect/sleep/trigger/loop)
-	Effect   string `json:"effect,omitempty"`    // The Effect type (if Type=effect)
-	Leds     string `json:"leds,omitempty"`      // Leds to use for the scene (optional) If not set and is required for the type, defaults to entire strip
-	Time     int    `json:"time,omitempty"`      // Time (in milliseconds).  Some things (like trigger) don't require time
-	MetaInfo any    `json:"meta-info,omitempty"` // Additional information required for specific types
-	Number   int    `json:"number"`              // The step number (ordinal position in the timeline)
+	ID         string          `json:"id"`                   // The timeline step id
+	Type       string          `json:"type"`                 // Timeline frame type (effect/sleep/trigger/loop/label)
+	Effect     string          `json:"effect,omitempty"`     // The Effect type (if Type=effect)
+	Leds       string          `json:"leds,omitempty"`       // Leds to use for the scene (optional) If not set and is required for the type, defaults to entire strip
+	Time       int             `json:"time,omitempty"`       // Time (in milliseconds).  Some things (like trigger) don't require time
+	MetaInfo   any             `json:"meta-info,omitempty"`  // Additional information required for specific types
+	Number     int             `json:"number"`               // The step number (ordinal position in the timeline)
+	Transition *StepTransition `json:"transition,omitempty"` // How to blend from the previous step into this one (optional)
+	Filters    []StepFilter    `json:"filters,omitempty"`    // Filters run over the frame after the effect draws it, in order (optional)
+}
+
+// StepTransition describes how a step blends in from whatever was showing before it
+type StepTransition struct {
+	Type     string `json:"type"`             // crossfade (default), wipe or dissolve
+	Duration int    `json:"duration"`         // Time (in milliseconds) the transition takes
+	Easing   string `json:"easing,omitempty"` // Easing curve: linear, ease-in, ease-out or ease-in-out (default)
+}
+
+// StepFilter describes a filter that's run over each frame after the effect draws it.  The amount is:
+//   - hue-shift: degrees to turn the hue (speed turns it a number of degrees a second)
+//   - saturation and brightness: how much to multiply by (0.5 halves it.  Saturation defaults to 0, brightness to 0.5)
+//   - blur: how far (in pixels) to blur, up to 32.  Defaults to 1
+//   - trails: how long (in milliseconds) trails take to fade.  Defaults to 500
+//   - posterize: the number of levels for each channel.  Defaults to 4
+//   - sparkle: sparkles a second, up to 1000.  Defaults to 20 (in white, unless there's a color)
+//   - tint: how much of the color to use (0 - 1).  Defaults to 0.5
+type StepFilter struct {
+	Type   string     `json:"type"`             // hue-shift, saturation, brightness, blur, trails, posterize, invert, sparkle or tint
+	Amount *float64   `json:"amount,omitempty"` // How strong the filter is (see above)
+	Speed  float64    `json:"speed,omitempty"`  // How fast the hue shifts (in degrees a second)
+	Color  *MetaColor `json:"color,omitempty"`  // The sparkle or tint color
+}
+
+// Palette represents a named set of colors that effects can use
+type Palette struct {
+	ID      string      `json:"id,omitempty"`      // Unique palette id
+	Name    string      `json:"name"`              // Palette name.  Effects reference the palette by this name
+	Created string      `json:"created,omitempty"` // Palette create time
+	Stops   []ColorStop `json:"stops"`             // Color stops for the palette
 }
 
+// Script represents a Lua script in the script store
+type Script struct {
+	Name     string `json:"name"`             // Script name.  Effects reference the script by this name
+	Size     int64  `json:"size"`             // Size of the script in bytes
+	Modified string `json:"modified"`         // Last time the script was changed
+	Source   string `json:"source,omitempty"` // The script source (only included when getting a single script)
+}
+
+// Image represents an uploaded image in the image store
+type Image struct {
+	ID      string `json:"id,omitempty"`      // Unique image id
+	Name    string `json:"name"`              // Image name.  Effects reference the image by this name (or id)
+	Created string `json:"created,omitempty"` // Image upload time
+	Width   int    `json:"width"`             // Width of the image in pixels
+	Height  int    `json:"height"`            // Height of the image in pixels
+}
+
+// ColorStop represents a color at a position in a palette
+type ColorStop struct {
+	Position float64   `json:"position"` // Position (0 - 1) of the color
+	Color    MetaColor `json:"color"`    // The color at this position
+}
+
+// MetaColor is a color.  It can also be passed as a color specification string
+// like "#FF8800", "hsv(30, 100%, 100%)", "orange" or "2700K"
 type MetaColor struct {
 	R int `json:"R,omitempty"`
 	G int `json:"G,omitempty"`
 	B int `json:"B,omitempty"`
 	W int `json:"W,omitempty"`
+	K int `json:"K,omitempty"` // Color temperature (in Kelvin), for colors given as a temperature
+}
+
+// UnmarshalJSON reads a color object or a color specification string
+func (c *MetaColor) UnmarshalJSON(b []byte) error {
+	color := data.MetaColor{}
+	if err := json.Unmarshal(b, &color); err != nil {
+		return err
+	}
+
+	*c = MetaColor{
+		R: color.R,
+		G: color.G,
+		B: color.B,
+		W: color.W,
+		K: color.K,
+	}
+	return nil
 }
 
 type SolidMeta struct {
@@ -40,12 +124,23 @@ type SolidMeta struct {
 }
 
 type FadeMeta struct {
-	Color MetaColor `json:"color"`
+	Color      MetaColor   `json:"color"`
+	Easing     string      `json:"easing,omitempty"` // linear (default), ease-in, ease-out, ease-in-out, ease-[in|out|in-out]-[quad|cubic|sine|expo|bounce|elastic] or cubic-bezier(x1, y1, x2, y2)
+	FromColor  *MetaColor  `json:"from-color,omitempty"`
+	From       string      `json:"from,omitempty"` // current: start from what the LEDs are actually showing
+	Sequence   []MetaColor `json:"sequence,omitempty"`
+	Stops      []ColorStop `json:"stops,omitempty"`
+	Palette    string      `json:"palette,omitempty"`
+	ColorSpace string      `json:"color-space,omitempty"`
 }
 
 type GradientMeta struct {
-	StartColor MetaColor `json:"start-color"`
-	EndColor   MetaColor `json:"end-color"`
+	StartColor MetaColor   `json:"start-color"`
+	EndColor   MetaColor   `json:"end-color"`
+	Stops      []ColorStop `json:"stops,omitempty"`
+	Palette    string      `json:"palette,omitempty"`
+	ColorSpace string      `json:"color-space,omitempty"`
+	Speed      float64     `json:"speed,omitempty"`
 }
 
 type SequenceMeta struct {
@@ -53,7 +148,8 @@ type SequenceMeta struct {
 }
 
 type ZipMeta struct {
-	Color MetaColor `json:"color"`
+	Color  MetaColor `json:"color"`
+	Easing string    `json:"easing,omitempty"` // linear (default), ease-in, ease-out, ease-in-out, ease-[in|out|in-out]-[quad|cubic|sine|expo|bounce|elastic] or cubic-bezier(x1, y1, x2, y2)
 }
 
 type LightningMeta struct {
@@ -62,6 +158,153 @@ type LightningMeta struct {
 	BurstSpacing    int    `json:"burst-spacing,omitempty"`
 	BurstLength     int    `json:"burst-length,omitempty"`
 	BurstBrightness int    `json:"burst-brightness,omitempty"`
+	StrikeSize      int    `json:"strike-size,omitempty"`
+	StrikeFalloff   int    `json:"strike-falloff,omitempty"`
+}
+
+type NoiseMeta struct {
+	Scale   float64     `json:"scale,omitempty"`
+	Speed   float64     `json:"speed,omitempty"`
+	Octaves int         `json:"octaves,omitempty"`
+	Colors  []MetaColor `json:"colors,omitempty"`
+	Palette string      `json:"palette,omitempty"`
+}
+
+type CandleMeta struct {
+	Temperature int     `json:"temperature,omitempty"`
+	Brightness  int     `json:"brightness,omitempty"`
+	Intensity   float64 `json:"intensity,omitempty"`
+	Wind        float64 `json:"wind,omitempty"`
+	GroupSize   int     `json:"group-size,omitempty"`
+}
+
+type BouncingBallsMeta struct {
+	Balls     int         `json:"balls,omitempty"`
+	Colors    []MetaColor `json:"colors,omitempty"`
+	Gravity   float64     `json:"gravity,omitempty"`
+	Dampening float64     `json:"dampening,omitempty"`
+	Relaunch  bool        `json:"relaunch,omitempty"`
+}
+
+type StrobeMeta struct {
+	Color     MetaColor `json:"color"`
+	Frequency float64   `json:"frequency,omitempty"`
+	DutyCycle float64   `json:"duty-cycle,omitempty"`
+	Flashes   int       `json:"flashes,omitempty"`
+}
+
+type BeaconSegment struct {
+	Leds    string      `json:"leds,omitempty"`
+	Colors  []MetaColor `json:"colors"`
+	Flashes int         `json:"flashes,omitempty"`
+	Offset  int         `json:"offset,omitempty"`
+	Rotate  bool        `json:"rotate,omitempty"`
+}
+
+type BeaconMeta struct {
+	Preset   string          `json:"preset,omitempty"`
+	Period   int             `json:"period,omitempty"`
+	Segments []BeaconSegment `json:"segments,omitempty"`
+}
+
+type ImageMeta struct {
+	Image   string  `json:"image"`
+	Rate    float64 `json:"rate,omitempty"`
+	Loops   int     `json:"loops,omitempty"`
+	Reverse bool    `json:"reverse,omitempty"`
+}
+
+type GifMeta struct {
+	Image string `json:"image"`
+	Loops int    `json:"loops,omitempty"`
+	Scale string `json:"scale,omitempty"`
+}
+
+type TextMeta struct {
+	Text      string      `json:"text"`
+	Font      string      `json:"font,omitempty"`
+	Color     MetaColor   `json:"color,omitempty"`
+	Colors    []MetaColor `json:"colors,omitempty"`
+	Palette   string      `json:"palette,omitempty"`
+	Speed     float64     `json:"speed,omitempty"`
+	Direction string      `json:"direction,omitempty"`
+	Repeat    int         `json:"repeat,omitempty"`
+}
+
+type ClockMeta struct {
+	HourColor   MetaColor `json:"hour-color,omitempty"`
+	MinuteColor MetaColor `json:"minute-color,omitempty"`
+	SecondColor MetaColor `json:"second-color,omitempty"`
+	MarkerColor MetaColor `json:"marker-color,omitempty"`
+	Format      string    `json:"format,omitempty"`
+	Font        string    `json:"font,omitempty"`
+	Timezone    string    `json:"timezone,omitempty"`
+	Offset      int       `json:"offset,omitempty"`
+	Reverse     bool      `json:"reverse,omitempty"`
+}
+
+type CountdownMeta struct {
+	Target   string    `json:"target,omitempty"`
+	Duration int       `json:"duration,omitempty"`
+	Color    MetaColor `json:"color,omitempty"`
+	Font     string    `json:"font,omitempty"`
+	Reverse  bool      `json:"reverse,omitempty"`
+}
+
+type VUMeterMeta struct {
+	Colors      []MetaColor `json:"colors,omitempty"`
+	Palette     string      `json:"palette,omitempty"`
+	PeakColor   MetaColor   `json:"peak-color,omitempty"`
+	Mirror      bool        `json:"mirror,omitempty"`
+	Sensitivity float64     `json:"sensitivity,omitempty"`
+}
+
+type SpectrumMeta struct {
+	Colors      []MetaColor `json:"colors,omitempty"`
+	Palette     string      `json:"palette,omitempty"`
+	Sensitivity float64     `json:"sensitivity,omitempty"`
+}
+
+type BeatPulseMeta struct {
+	Colors  []MetaColor `json:"colors,omitempty"`
+	Palette string      `json:"palette,omitempty"`
+	Decay   int         `json:"decay,omitempty"`
+}
+
+type FormulaMeta struct {
+	Formula string `json:"formula"` // Inputs: index, x, y, t, count, width, height, level, beat.  Outputs: h, s, v or r, g, b, w (all 0 - 1)
+}
+
+type ScriptMeta struct {
+	Script string         `json:"script"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
+type SunriseMeta struct {
+	NightTemperature int `json:"night-temperature,omitempty"`
+	DayTemperature   int `json:"day-temperature,omitempty"`
+	Brightness       int `json:"brightness,omitempty"`
+}
+
+type CircadianMeta struct {
+	Keyframes []CircadianKeyframe `json:"keyframes,omitempty"`
+}
+
+// CircadianKeyframe is the light at a time of day
+type CircadianKeyframe struct {
+	Time        string `json:"time"`        // Time of day (local time), like '07:30' or '21:00'
+	Temperature int    `json:"temperature"` // Color temperature (in Kelvin)
+	Brightness  int    `json:"brightness"`  // Brightness (0 - 255)
+}
+
+type LoopMeta struct {
+	Count int    `json:"count,omitempty"` // How many times the steps in the loop play.  0 (or not set) loops forever
+	Step  int    `json:"step,omitempty"`  // The step number to go back to.  Defaults to the first step
+	Label string `json:"label,omitempty"` // The name of the label step to go back to (instead of a step number)
+}
+
+type LabelMeta struct {
+	Name string `json:"name"` // What loops call the label
 }
 
 type TriggerMeta struct {
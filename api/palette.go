@@ -0,0 +1,258 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/danesparza/fxpixel/internal/data"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"strings"
+)
+
+// GetAllPalettes godoc
+// @Summary List all palettes in the system
+// @Description List all palettes in the system
+// @Tags palette
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} api.SystemResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /palettes [get]
+func (service Service) GetAllPalettes(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get a list of palettes
+	dbPalettes, err := service.DB.GetAllPalettes(req.Context())
+	if err != nil {
+		err = fmt.Errorf("error getting a list of palettes: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	For each palette, convert it to the API model:
+	retval := []Palette{}
+	for _, palette := range dbPalettes {
+		retval = append(retval, PaletteToApi(palette))
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("%v palette(s)", len(retval)),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
+
+// AddPalette godoc
+// @Summary Adds a palette to the system
+// @Description Adds a palette to the system
+// @Tags palette
+// @Accept  json
+// @Produce  json
+// @Param endpoint body api.Palette true "The palette to add"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /palettes [put]
+func (service Service) AddPalette(rw http.ResponseWriter, req *http.Request) {
+
+	//	Parse the body
+	request := Palette{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		err = fmt.Errorf("problem decoding add palette request: %v", err)
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Make sure we have what we need
+	if err := checkPalette(request); err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Add a palette
+	dbPalette, err := service.DB.AddPalette(req.Context(), ApiToPalette(request))
+	if err != nil {
+		err = fmt.Errorf("error adding a palette: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Convert the palette to the API model:
+	retval := PaletteToApi(dbPalette)
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("Palette added: %v", retval.ID),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
+
+// GetPalette godoc
+// @Summary Gets a single palette
+// @Description Gets a single palette
+// @Tags palette
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The palette id to get"
+// @Success 200 {object} api.SystemResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /palettes/{id} [get]
+func (service Service) GetPalette(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the id from the url
+	paletteId := chi.URLParam(req, "id")
+
+	//	Get the palette
+	dbPalette, err := service.DB.GetPalette(req.Context(), paletteId)
+	if errors.Is(err, data.ErrPaletteNotFound) {
+		sendErrorResponse(rw, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("error getting a palette: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Convert the palette to the API model:
+	retval := PaletteToApi(dbPalette)
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("Palette fetched: %v", retval.ID),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
+
+// UpdatePalette godoc
+// @Summary Updates a palette
+// @Description Updates the name and color stops for a palette
+// @Tags palette
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The palette id to update"
+// @Param endpoint body api.Palette true "The updated palette"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /palettes/{id} [post]
+func (service Service) UpdatePalette(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the id from the url (if it's blank, return an error)
+	paletteId := chi.URLParam(req, "id")
+
+	if paletteId == "" {
+		err := fmt.Errorf("requires an id of a palette to update")
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Parse the body
+	request := Palette{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		err = fmt.Errorf("problem decoding palette update request: %v", err)
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Make sure we have what we need
+	if err := checkPalette(request); err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Update the palette
+	dbPalette, err := service.DB.UpdatePalette(req.Context(), paletteId, ApiToPalette(request))
+	if err != nil {
+		err = fmt.Errorf("error updating palette: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: "Palette updated",
+		Data:    PaletteToApi(dbPalette),
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
+
+// DeletePalette godoc
+// @Summary Delete a single palette
+// @Description Delete a single palette
+// @Tags palette
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The palette id to delete"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /palettes/{id} [delete]
+func (service Service) DeletePalette(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the id from the url (if it's blank, return an error)
+	paletteId := chi.URLParam(req, "id")
+
+	if paletteId == "" {
+		err := fmt.Errorf("requires an id of a palette to delete")
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Delete the palette
+	err := service.DB.DeletePalette(req.Context(), paletteId)
+	if err != nil {
+		err = fmt.Errorf("error deleting a palette: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("Palette deleted: %v", paletteId),
+		Data:    paletteId,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
+
+// checkPalette makes sure a palette has a name and at least one color stop, and that
+// the stops are positioned along the palette (0 - 1)
+func checkPalette(p Palette) error {
+	if strings.TrimSpace(p.Name) == "" || len(p.Stops) == 0 {
+		return fmt.Errorf("a palette requires a name and at least one color stop")
+	}
+
+	for i, stop := range p.Stops {
+		if stop.Position < 0 || stop.Position > 1 {
+			return fmt.Errorf("color stop %v is at %v.  Stops go from 0 to 1", i+1, stop.Position)
+		}
+	}
+
+	return nil
+}
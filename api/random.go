@@ -0,0 +1,132 @@
+package api
+
+import (
+	"encoding/json"
+	"github.com/danesparza/fxpixel/internal/data"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// extractRandom pulls the random values (like {"min": 2, "max": 6}) out of decoded JSON meta
+// info and adds them to random, by path.  They're replaced with null, so the rest of the meta
+// info reads as usual.  The meta info type (t) says where random values can go: only typed
+// values are extracted, and free-form values (like script params) are left as they are
+func extractRandom(value any, t reflect.Type, path string, random map[string]data.RandomValue) (any, error) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() == reflect.Interface || t.Kind() == reflect.Map {
+		return value, nil
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if data.IsRandomValue(v) && !hasJSONFields(t, v) {
+			rv := data.RandomValue{}
+			spec, _ := json.Marshal(v)
+			if err := json.Unmarshal(spec, &rv); err != nil {
+				return nil, err
+			}
+			random[path] = rv
+			return nil, nil
+		}
+
+		for key, item := range v {
+			field, found := jsonField(t, key)
+			if !found {
+				continue
+			}
+			extracted, err := extractRandom(item, field, joinPath(path, key), random)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = extracted
+		}
+	case []any:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			break
+		}
+		for i, item := range v {
+			extracted, err := extractRandom(item, t.Elem(), joinPath(path, strconv.Itoa(i)), random)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = extracted
+		}
+	}
+
+	return value, nil
+}
+
+// jsonField returns the type of the struct field with the passed JSON name
+func jsonField(t reflect.Type, name string) (reflect.Type, bool) {
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag == name && t.Field(i).IsExported() {
+			return t.Field(i).Type, true
+		}
+	}
+	return nil, false
+}
+
+// hasJSONFields returns true if every key in the object is a field of the struct, so the
+// object is a regular value even if it looks like a random one
+func hasJSONFields(t reflect.Type, object map[string]any) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for key := range object {
+		if _, found := jsonField(t, key); !found {
+			return false
+		}
+	}
+	return true
+}
+
+// inlineRandom puts the random values back into the meta info, so they read the
+// same way they were added
+func inlineRandom(meta any, random map[string]data.RandomValue) any {
+	encoded, _ := json.Marshal(meta)
+	var retval any
+	if err := json.Unmarshal(encoded, &retval); err != nil || retval == nil {
+		retval = map[string]any{}
+	}
+
+	for path, rv := range random {
+		retval = setJSONPath(retval, strings.Split(path, "."), rv)
+	}
+
+	return retval
+}
+
+// setJSONPath sets the value at path in decoded JSON, adding objects as needed
+func setJSONPath(node any, path []string, value any) any {
+	if len(path) == 0 {
+		return value
+	}
+
+	if items, ok := node.([]any); ok {
+		if i, err := strconv.Atoi(path[0]); err == nil && i >= 0 && i < len(items) {
+			items[i] = setJSONPath(items[i], path[1:], value)
+			return items
+		}
+	}
+
+	object, ok := node.(map[string]any)
+	if !ok {
+		object = map[string]any{}
+	}
+	object[path[0]] = setJSONPath(object[path[0]], path[1:], value)
+	return object
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -27,6 +27,12 @@ type Service struct {
 
 	//	StopAllTimelines signals all timelines should stop playing
 	StopAllTimelines chan bool
+
+	// ImageDir is the directory uploaded images are stored in
+	ImageDir string
+
+	// ScriptDir is the directory scripts are stored in
+	ScriptDir string
 }
 
 // UpdateTagsRequest represents a request to update tags for a file
@@ -34,11 +40,6 @@ type UpdateTagsRequest struct {
 	Tags []string `json:"tags"`
 }
 
-// UpdateConfigValueRequest represents a request to update a system config value
-type UpdateConfigValueRequest struct {
-	Value int `json:"value"`
-}
-
 // SystemResponse is a response for a system request
 type SystemResponse struct {
 	Message string      `json:"message"`
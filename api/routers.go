@@ -48,6 +48,31 @@ func NewRouter(apiService Service) http.Handler {
 			r.Post("/{id}", apiService.UpdateTags)                 // Update timeline tags
 		})
 
+		//	Palette management
+		r.Route("/palettes", func(r chi.Router) {
+			r.Put("/", apiService.AddPalette)           // Add a palette
+			r.Get("/", apiService.GetAllPalettes)       // Get all palettes
+			r.Get("/{id}", apiService.GetPalette)       // Get a single palette
+			r.Post("/{id}", apiService.UpdatePalette)   // Update a palette
+			r.Delete("/{id}", apiService.DeletePalette) // Delete a palette
+		})
+
+		//	Image store
+		r.Route("/images", func(r chi.Router) {
+			r.Put("/", apiService.AddImage)           // Upload an image
+			r.Get("/", apiService.GetAllImages)       // Get all images
+			r.Get("/{id}", apiService.GetImage)       // Get a single image
+			r.Delete("/{id}", apiService.DeleteImage) // Delete an image
+		})
+
+		//	Script store
+		r.Route("/scripts", func(r chi.Router) {
+			r.Put("/", apiService.AddScript)             // Upload a script
+			r.Get("/", apiService.GetAllScripts)         // Get all scripts
+			r.Get("/{name}", apiService.GetScript)       // Get a single script
+			r.Delete("/{name}", apiService.DeleteScript) // Delete a script
+		})
+
 		//	Run or stop a timeline
 		r.Route("/timeline", func(r chi.Router) {
 			//r.Post("/run/random/{tag}", apiService.ShowUI)        // Run a random timeline in a tag
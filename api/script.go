@@ -0,0 +1,202 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/danesparza/fxpixel/internal/data"
+	"github.com/go-chi/chi/v5"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// maxScriptUploadSize is the largest script (in bytes) we'll accept
+const maxScriptUploadSize = 1 << 20
+
+// GetAllScripts godoc
+// @Summary List all scripts in the script store
+// @Description List all scripts in the script store
+// @Tags script
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} api.SystemResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /scripts [get]
+func (service Service) GetAllScripts(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get a list of scripts
+	scripts, err := data.GetAllScripts(service.ScriptDir)
+	if err != nil {
+		err = fmt.Errorf("error getting a list of scripts: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	For each script, convert it to the API model:
+	retval := []Script{}
+	for _, script := range scripts {
+		retval = append(retval, ScriptToApi(script, ""))
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("%v script(s)", len(retval)),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
+
+// AddScript godoc
+// @Summary Uploads a script to the script store
+// @Description Uploads a Lua script to the script store.  A script with the same name is replaced
+// @Tags script
+// @Accept  mpfd
+// @Produce  json
+// @Param file formData file true "The Lua script to upload"
+// @Param name formData string false "The script name (letters, numbers, '-' and '_').  Defaults to the file name"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /scripts [put]
+func (service Service) AddScript(rw http.ResponseWriter, req *http.Request) {
+
+	//	Parse the form
+	req.Body = http.MaxBytesReader(rw, req.Body, maxScriptUploadSize)
+	if err := req.ParseMultipartForm(maxScriptUploadSize); err != nil {
+		err = fmt.Errorf("problem parsing script upload: %v", err)
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	file, header, err := req.FormFile("file")
+	if err != nil {
+		err = fmt.Errorf("a script upload requires a file: %v", err)
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	source, err := io.ReadAll(file)
+	if err != nil {
+		err = fmt.Errorf("problem reading script: %v", err)
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Default the name to the file name (without the extension)
+	name := strings.TrimSpace(req.FormValue("name"))
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(header.Filename), filepath.Ext(header.Filename))
+	}
+
+	//	Save the script (this checks the name and makes sure it compiles)
+	script, err := data.SaveScript(service.ScriptDir, name, string(source))
+	if err != nil {
+		err = fmt.Errorf("error adding a script: %v", err)
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Convert the script to the API model:
+	retval := ScriptToApi(script, "")
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("Script added: %v", retval.Name),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
+
+// GetScript godoc
+// @Summary Gets a single script
+// @Description Gets a single script (including its source)
+// @Tags script
+// @Accept  json
+// @Produce  json
+// @Param name path string true "The script name to get"
+// @Success 200 {object} api.SystemResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /scripts/{name} [get]
+func (service Service) GetScript(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the name from the url
+	name := chi.URLParam(req, "name")
+
+	//	Get the script
+	script, source, err := data.GetScript(service.ScriptDir, name)
+	if errors.Is(err, data.ErrScriptNotFound) {
+		sendErrorResponse(rw, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("error getting a script: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Convert the script to the API model:
+	retval := ScriptToApi(script, source)
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("Script fetched: %v", retval.Name),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
+
+// DeleteScript godoc
+// @Summary Deletes a script in the system
+// @Description Deletes a script from the script store
+// @Tags script
+// @Accept  json
+// @Produce  json
+// @Param name path string true "The script name to delete"
+// @Success 200 {object} api.SystemResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /scripts/{name} [delete]
+func (service Service) DeleteScript(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the name from the url
+	name := chi.URLParam(req, "name")
+
+	//	Delete the script
+	err := data.DeleteScript(service.ScriptDir, name)
+	if errors.Is(err, data.ErrScriptNotFound) {
+		sendErrorResponse(rw, err, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("error deleting a script: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("Script deleted: %v", name),
+		Data:    name,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+
+}
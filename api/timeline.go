@@ -109,7 +109,12 @@ func (service Service) AddTimeline(rw http.ResponseWriter, req *http.Request) {
 	}
 
 	//	Convert the api request into a data model:
-	newTimeline := ApiToTimeline(request)
+	newTimeline, err := ApiToTimeline(request)
+	if err != nil {
+		err = fmt.Errorf("problem with the add timeline request: %v", err)
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
 
 	//	Add a timeline
 	dbTimeline, err := service.DB.AddTimeline(req.Context(), newTimeline)
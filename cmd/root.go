@@ -52,7 +52,17 @@ func init() {
 
 	//	Set our defaults
 	viper.SetDefault("datastore.system", path.Join(home, "fxpixel", "db", "fxpixel.db"))
+	viper.SetDefault("datastore.images", path.Join(home, "fxpixel", "images"))
+	viper.SetDefault("datastore.scripts", path.Join(home, "fxpixel", "lua"))
 	viper.SetDefault("server.port", "3050")
+	viper.SetDefault("effects.strobe-max-frequency", 0)
+	viper.SetDefault("effects.script-frame-limit", 50)
+	viper.SetDefault("audio.input", "")
+	viper.SetDefault("audio.sample-rate", 44100)
+	viper.SetDefault("audio.channels", 1)
+	viper.SetDefault("audio.wled-sync", false)
+	viper.SetDefault("audio.wled-port", 11988)
+	viper.SetDefault("audio.wled-interface", "")
 
 	// If a config file is found, read it in
 	viper.ReadInConfig()
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/danesparza/fxpixel/api"
 	_ "github.com/danesparza/fxpixel/docs" // swagger docs location
+	"github.com/danesparza/fxpixel/internal/audio"
 	"github.com/danesparza/fxpixel/internal/data"
 	"github.com/danesparza/fxpixel/internal/leds"
 	"github.com/rs/zerolog/log"
@@ -35,10 +36,14 @@ func start(cmd *cobra.Command, args []string) {
 	}
 
 	systemdb := viper.GetString("datastore.system")
+	imagedir := viper.GetString("datastore.images")
+	scriptdir := viper.GetString("datastore.scripts")
 
 	//	Emit what we know:
 	log.Info().
 		Str("systemdb", systemdb).
+		Str("imagedir", imagedir).
+		Str("scriptdir", scriptdir).
 		Msg("Starting up")
 
 	//	Init SQLite
@@ -52,12 +57,38 @@ func start(cmd *cobra.Command, args []string) {
 	//	Init the AppDataService
 	appdata := data.NewAppDataService(db)
 
+	//	Trap program exit appropriately
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal, 2)
+	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+	go handleSignals(ctx, sigs, cancel)
+
+	//	Start the audio input, if we have one
+	var audioInput audio.Input
+	if viper.GetBool("audio.wled-sync") {
+		if viper.GetString("audio.input") != "" {
+			log.Warn().Msg("WLED audio sync is enabled -- ignoring audio.input")
+		}
+		wled := audio.NewWLEDInput(viper.GetInt("audio.wled-port"), viper.GetString("audio.wled-interface"))
+		go wled.Run(ctx)
+		audioInput = wled
+	} else if input := viper.GetString("audio.input"); input != "" {
+		pcm := audio.NewPCMInput(input, viper.GetInt("audio.sample-rate"), viper.GetInt("audio.channels"))
+		go pcm.Run(ctx)
+		audioInput = pcm
+	}
+
 	//	Create a background service object
 	backgroundService := leds.BackgroundProcess{
-		PlayTimeline:     make(chan leds.PlayTimelineRequest),
-		StopTimeline:     make(chan string),
-		StopAllTimelines: make(chan bool),
-		DB:               appdata,
+		PlayTimeline:       make(chan leds.PlayTimelineRequest),
+		StopTimeline:       make(chan string),
+		StopAllTimelines:   make(chan bool),
+		DB:                 appdata,
+		MaxStrobeFrequency: viper.GetFloat64("effects.strobe-max-frequency"),
+		ImageDir:           imagedir,
+		Audio:              audioInput,
+		ScriptDir:          scriptdir,
+		ScriptFrameLimit:   time.Duration(viper.GetInt("effects.script-frame-limit")) * time.Millisecond,
 	}
 
 	//	Create an api service object
@@ -67,14 +98,10 @@ func start(cmd *cobra.Command, args []string) {
 		StopAllTimelines: backgroundService.StopAllTimelines,
 		DB:               appdata,
 		StartTime:        time.Now(),
+		ImageDir:         imagedir,
+		ScriptDir:        scriptdir,
 	}
 
-	//	Trap program exit appropriately
-	ctx, cancel := context.WithCancel(context.Background())
-	sigs := make(chan os.Signal, 2)
-	signal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
-	go handleSignals(ctx, sigs, cancel)
-
 	//	Set up the API routes
 	r := api.NewRouter(apiService)
 
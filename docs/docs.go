@@ -48,6 +48,530 @@ const docTemplate = `{
                 }
             }
         },
+        "/images": {
+            "get": {
+                "description": "List all images in the image store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "image"
+                ],
+                "summary": "List all images in the image store",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Uploads an image (png, gif or jpeg) to the image store",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "image"
+                ],
+                "summary": "Uploads an image to the image store",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "The image to upload",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The image name.  Defaults to the file name",
+                        "name": "name",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/images/{id}": {
+            "get": {
+                "description": "Gets information about a single image",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "image"
+                ],
+                "summary": "Gets a single image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The image id to get",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes an image from the image store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "image"
+                ],
+                "summary": "Deletes an image in the system",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The image id to delete",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/palettes": {
+            "get": {
+                "description": "List all palettes in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "palette"
+                ],
+                "summary": "List all palettes in the system",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Adds a palette to the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "palette"
+                ],
+                "summary": "Adds a palette to the system",
+                "parameters": [
+                    {
+                        "description": "The palette to add",
+                        "name": "endpoint",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.Palette"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/palettes/{id}": {
+            "get": {
+                "description": "Gets a single palette",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "palette"
+                ],
+                "summary": "Gets a single palette",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The palette id to get",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Updates the name and color stops for a palette",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "palette"
+                ],
+                "summary": "Updates a palette",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The palette id to update",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The updated palette",
+                        "name": "endpoint",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.Palette"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a single palette",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "palette"
+                ],
+                "summary": "Delete a single palette",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The palette id to delete",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/scripts": {
+            "get": {
+                "description": "List all scripts in the script store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "script"
+                ],
+                "summary": "List all scripts in the script store",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Uploads a Lua script to the script store.  A script with the same name is replaced",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "script"
+                ],
+                "summary": "Uploads a script to the script store",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "The Lua script to upload",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The script name (letters, numbers, '-' and '_').  Defaults to the file name",
+                        "name": "name",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/scripts/{name}": {
+            "get": {
+                "description": "Gets a single script (including its source)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "script"
+                ],
+                "summary": "Gets a single script",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The script name to get",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a script from the script store",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "script"
+                ],
+                "summary": "Deletes a script in the system",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The script name to delete",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/timeline/run/{id}": {
             "post": {
                 "description": "Plays a timeline in the system",
@@ -391,6 +915,23 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "api.ColorStop": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "description": "The color at this position",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/api.MetaColor"
+                        }
+                    ]
+                },
+                "position": {
+                    "description": "Position (0 - 1) of the color",
+                    "type": "number"
+                }
+            }
+        },
         "api.ErrorResponse": {
             "type": "object",
             "properties": {
@@ -399,6 +940,93 @@ const docTemplate = `{
                 }
             }
         },
+        "api.MetaColor": {
+            "type": "object",
+            "properties": {
+                "B": {
+                    "type": "integer"
+                },
+                "G": {
+                    "type": "integer"
+                },
+                "K": {
+                    "description": "Color temperature (in Kelvin), for colors given as a temperature",
+                    "type": "integer"
+                },
+                "R": {
+                    "type": "integer"
+                },
+                "W": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.Palette": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "description": "Palette create time",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique palette id",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Palette name.  Effects reference the palette by this name",
+                    "type": "string"
+                },
+                "stops": {
+                    "description": "Color stops for the palette",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.ColorStop"
+                    }
+                }
+            }
+        },
+        "api.StepFilter": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "How strong the filter is (see above)",
+                    "type": "number"
+                },
+                "color": {
+                    "description": "The sparkle or tint color",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/api.MetaColor"
+                        }
+                    ]
+                },
+                "speed": {
+                    "description": "How fast the hue shifts (in degrees a second)",
+                    "type": "number"
+                },
+                "type": {
+                    "description": "hue-shift, saturation, brightness, blur, trails, posterize, invert, sparkle or tint",
+                    "type": "string"
+                }
+            }
+        },
+        "api.StepTransition": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "Time (in milliseconds) the transition takes",
+                    "type": "integer"
+                },
+                "easing": {
+                    "description": "Easing curve: linear, ease-in, ease-out or ease-in-out (default)",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "crossfade (default), wipe or dissolve",
+                    "type": "string"
+                }
+            }
+        },
         "api.SystemResponse": {
             "type": "object",
             "properties": {
@@ -419,6 +1047,13 @@ const docTemplate = `{
                     "description": "Timeline enabled or not",
                     "type": "boolean"
                 },
+                "filters": {
+                    "description": "Filters for every effect in the timeline (after the step's own filters)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.StepFilter"
+                    }
+                },
                 "gpio": {
                     "description": "The GPIO device to play the timeline on.  Optional.  If not set, uses the default",
                     "type": "integer"
@@ -454,6 +1089,13 @@ const docTemplate = `{
                     "description": "The Effect type (if Type=effect)",
                     "type": "string"
                 },
+                "filters": {
+                    "description": "Filters run over the frame after the effect draws it, in order (optional)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.StepFilter"
+                    }
+                },
                 "id": {
                     "description": "The timeline step id",
                     "type": "string"
@@ -473,8 +1115,16 @@ const docTemplate = `{
                     "description": "Time (in milliseconds).  Some things (like trigger) don't require time",
                     "type": "integer"
                 },
+                "transition": {
+                    "description": "How to blend from the previous step into this one (optional)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/api.StepTransition"
+                        }
+                    ]
+                },
                 "type": {
-                    "description": "Timeline frame type (effect/sleep/trigger/loop)",
+                    "description": "Timeline frame type (effect/sleep/trigger/loop/label)",
                     "type": "string"
                 }
             }
@@ -0,0 +1,166 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+	"time"
+)
+
+// WindowSize is the number of samples analyzed in each frame
+const WindowSize = 1024
+
+const (
+	bandLow      = 40.0    // Lowest frequency (in Hz) of the first band
+	bandHigh     = 16000.0 // Highest frequency (in Hz) of the last band
+	gainDecay    = 0.995   // How quickly the automatic gain forgets loud moments (per frame)
+	levelFloor   = 0.01    // Quietest level the automatic gain will scale up
+	bandFloor    = 5.0     // Quietest band magnitude the automatic gain will scale up
+	fallOff      = 0.85    // How quickly bands and level fall back after a peak (per frame)
+	beatHistory  = 43      // Frames of bass history to compare against (about a second)
+	beatRatio    = 1.5     // How much louder than average the bass needs to be to count as a beat
+	beatCooldown = 150 * time.Millisecond
+)
+
+// Analyzer turns blocks of samples into frames with level, beat and frequency band information
+type Analyzer struct {
+	frameStore
+	sampleRate int
+	window     []float64
+	bandBins   [Bands + 1]int
+	levelMax   float64
+	bandMax    [Bands]float64
+	level      float64
+	bands      [Bands]float64
+	bass       []float64
+	beats      int
+	lastBeat   time.Time
+}
+
+// NewAnalyzer creates an analyzer for audio at the passed sample rate
+func NewAnalyzer(sampleRate int) *Analyzer {
+	a := Analyzer{}
+
+	//	Hann window, to keep the edges of each block from smearing the spectrum
+	a.window = make([]float64, WindowSize)
+	for i := range a.window {
+		a.window[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(WindowSize-1)))
+	}
+
+	a.setSampleRate(sampleRate)
+
+	return &a
+}
+
+// setSampleRate works out which fft bins belong to each band
+func (a *Analyzer) setSampleRate(sampleRate int) {
+	a.sampleRate = sampleRate
+
+	//	Space the bands logarithmically, the way we hear them
+	high := math.Min(bandHigh, float64(sampleRate)/2)
+	binWidth := float64(sampleRate) / WindowSize
+	for i := 0; i <= Bands; i++ {
+		freq := bandLow * math.Pow(high/bandLow, float64(i)/Bands)
+		a.bandBins[i] = int(math.Round(freq / binWidth))
+	}
+}
+
+// Process analyzes a block of mono samples (-1 to 1) and publishes the resulting frame
+func (a *Analyzer) Process(samples []float64, now time.Time) Frame {
+	frame := Frame{Time: now}
+
+	//	Level
+	sum := 0.0
+	for _, s := range samples {
+		sum += s * s
+		frame.Peak = math.Max(frame.Peak, math.Abs(s))
+	}
+	rms := 0.0
+	if len(samples) > 0 {
+		rms = math.Sqrt(sum / float64(len(samples)))
+	}
+	a.levelMax = math.Max(rms, math.Max(a.levelMax*gainDecay, levelFloor))
+	a.level = math.Max(rms/a.levelMax, a.level*fallOff)
+	frame.Level = a.level
+
+	//	Frequency bands
+	spectrum := make([]complex128, WindowSize)
+	for i := 0; i < WindowSize && i < len(samples); i++ {
+		spectrum[i] = complex(samples[i]*a.window[i], 0)
+	}
+	fft(spectrum)
+
+	raw := [Bands]float64{}
+	for b := 0; b < Bands; b++ {
+		lo := a.bandBins[b]
+		hi := a.bandBins[b+1]
+		if hi <= lo {
+			hi = lo + 1
+		}
+
+		total := 0.0
+		for bin := lo; bin < hi && bin < WindowSize/2; bin++ {
+			total += cmplx.Abs(spectrum[bin])
+		}
+		raw[b] = total / float64(hi-lo)
+
+		a.bandMax[b] = math.Max(raw[b], math.Max(a.bandMax[b]*gainDecay, bandFloor))
+		a.bands[b] = math.Max(raw[b]/a.bandMax[b], a.bands[b]*fallOff)
+	}
+	frame.Bands = a.bands
+
+	//	Beat: a jump in bass energy compared to the last second or so
+	bass := raw[0] + raw[1] + raw[2]
+	average := 0.0
+	for _, e := range a.bass {
+		average += e
+	}
+	if len(a.bass) > 0 {
+		average /= float64(len(a.bass))
+	}
+
+	if len(a.bass) == beatHistory && bass > average*beatRatio && bass > bandFloor && now.Sub(a.lastBeat) > beatCooldown {
+		frame.Beat = true
+		a.beats++
+		a.lastBeat = now
+	}
+	frame.Beats = a.beats
+
+	a.bass = append(a.bass, bass)
+	if len(a.bass) > beatHistory {
+		a.bass = a.bass[1:]
+	}
+
+	a.set(frame)
+	return frame
+}
+
+// fft is an in place radix-2 fast fourier transform.  The length of x must be a power of 2
+func fft(x []complex128) {
+	n := len(x)
+
+	//	Bit reversal permutation
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even := x[start+k]
+				odd := x[start+k+size/2] * w
+				x[start+k] = even + odd
+				x[start+k+size/2] = even - odd
+				w *= step
+			}
+		}
+	}
+}
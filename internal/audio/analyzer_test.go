@@ -0,0 +1,78 @@
+package audio_test
+
+import (
+	"github.com/danesparza/fxpixel/internal/audio"
+	"math"
+	"testing"
+	"time"
+)
+
+func sine(freq, amplitude float64, sampleRate int) []float64 {
+	retval := make([]float64, audio.WindowSize)
+	for i := range retval {
+		retval[i] = amplitude * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate))
+	}
+	return retval
+}
+
+func TestAnalyzerBands(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		freq     float64
+		wantBand int
+	}{
+		{name: "Bass tone lands in a low band", freq: 60, wantBand: 1},
+		{name: "Middle tone lands in a middle band", freq: 1000, wantBand: 7},
+		{name: "High tone lands in a high band", freq: 10000, wantBand: 14},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := audio.NewAnalyzer(44100)
+			frame := a.Process(sine(tt.freq, 0.5, 44100), time.Now())
+
+			loudest := 0
+			for b, v := range frame.Bands {
+				if v > frame.Bands[loudest] {
+					loudest = b
+				}
+			}
+
+			if loudest < tt.wantBand-1 || loudest > tt.wantBand+1 {
+				t.Errorf("Loudest band = %v, want around %v (bands: %v)", loudest, tt.wantBand, frame.Bands)
+			}
+
+			if frame.Level < 0.9 {
+				t.Errorf("Level = %v, want close to 1", frame.Level)
+			}
+		})
+	}
+}
+
+func TestAnalyzerBeat(t *testing.T) {
+	a := audio.NewAnalyzer(44100)
+	now := time.Now()
+	quiet := sine(80, 0.01, 44100)
+	kick := sine(80, 0.8, 44100)
+
+	//	A second of quiet, then a kick drum
+	for i := 0; i < 50; i++ {
+		now = now.Add(23 * time.Millisecond)
+		if frame := a.Process(quiet, now); frame.Beat {
+			t.Fatalf("Beat detected in quiet audio at frame %v", i)
+		}
+	}
+
+	now = now.Add(23 * time.Millisecond)
+	frame := a.Process(kick, now)
+	if !frame.Beat || frame.Beats != 1 {
+		t.Errorf("Beat = %v (beats %v), want a beat", frame.Beat, frame.Beats)
+	}
+
+	//	Beats right on top of each other are ignored
+	now = now.Add(23 * time.Millisecond)
+	if frame := a.Process(kick, now); frame.Beat {
+		t.Errorf("Beat detected again during the cooldown")
+	}
+}
@@ -0,0 +1,52 @@
+package audio
+
+import (
+	"sync"
+	"time"
+)
+
+// Bands is the number of frequency bands in a frame
+const Bands = 16
+
+// staleAfter is how old a frame can get before we treat the input as silent
+const staleAfter = 500 * time.Millisecond
+
+// Frame is a snapshot of the analyzed audio
+type Frame struct {
+	Level float64        // Overall loudness (0 - 1)
+	Peak  float64        // Loudest sample in the frame (0 - 1)
+	Beat  bool           // A beat was detected in this frame
+	Beats int            // Number of beats detected so far.  Effects can use this to spot new beats
+	Bands [Bands]float64 // Loudness of each frequency band (0 - 1), from low to high
+	Time  time.Time      // When the frame was analyzed
+}
+
+// Input is a source of analyzed audio
+type Input interface {
+	// Latest returns the most recent frame.  If the input has gone quiet, this is a silent frame
+	Latest() Frame
+}
+
+// frameStore keeps the latest frame, safe for concurrent use
+type frameStore struct {
+	mu     sync.RWMutex
+	latest Frame
+}
+
+func (s *frameStore) set(f Frame) {
+	s.mu.Lock()
+	s.latest = f
+	s.mu.Unlock()
+}
+
+// Latest returns the most recent frame, or a silent frame if it's stale
+func (s *frameStore) Latest() Frame {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if time.Since(s.latest.Time) > staleAfter {
+		return Frame{Beats: s.latest.Beats}
+	}
+
+	return s.latest
+}
@@ -0,0 +1,197 @@
+package audio
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"io"
+	"os"
+	"time"
+)
+
+// maxFormatSize is the largest wav format chunk we'll read.  PCM formats are 16 to 40 bytes
+const maxFormatSize = 256
+
+// PCMInput reads 16 bit PCM audio from a WAV file, a named pipe or stdin and analyzes it.
+// Input that starts with a WAV header uses the format in the header.  Anything else is
+// treated as raw, little endian PCM in the configured format
+type PCMInput struct {
+	*Analyzer
+	path       string
+	sampleRate int
+	channels   int
+}
+
+// NewPCMInput creates a PCM input.  Path can be a WAV file, a named pipe or "-" for stdin.
+// Sample rate and channels describe raw PCM (and are replaced by the header for WAV input)
+func NewPCMInput(path string, sampleRate, channels int) *PCMInput {
+	p := PCMInput{}
+	p.path = path
+	p.sampleRate = sampleRate
+	p.channels = channels
+	p.Analyzer = NewAnalyzer(sampleRate)
+	return &p
+}
+
+// Run reads and analyzes audio until the context is cancelled.  Files are looped, and
+// pipes are re-opened when the writer goes away
+func (p *PCMInput) Run(ctx context.Context) {
+	log.Info().Str("input", p.path).Msg("Starting audio input")
+
+	for {
+		err := p.play(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Stopping audio input")
+			return
+		default:
+		}
+
+		switch {
+		case err == nil && p.path == "-":
+			log.Info().Msg("Audio input on stdin has ended")
+			return
+		case err != nil:
+			log.Err(err).Str("input", p.path).Msg("Problem reading audio input.  Retrying")
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// play reads the input once, until the end of the file (or pipe)
+func (p *PCMInput) play(ctx context.Context) error {
+	var f *os.File
+	if p.path == "-" {
+		f = os.Stdin
+	} else {
+		var err error
+		f, err = os.Open(p.path)
+		if err != nil {
+			return fmt.Errorf("problem opening audio input: %v", err)
+		}
+		defer f.Close()
+
+		//	Unblock reads if we're asked to stop
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-ctx.Done():
+				f.Close()
+			case <-done:
+			}
+		}()
+	}
+
+	//	Regular files are read as fast as the disk allows, so we need to play them back in real time
+	paced := false
+	if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+		paced = true
+	}
+
+	r := bufio.NewReader(f)
+	sampleRate, channels, err := readHeader(r, p.sampleRate, p.channels)
+	if err != nil {
+		return err
+	}
+	if sampleRate != p.Analyzer.sampleRate {
+		p.Analyzer.setSampleRate(sampleRate)
+	}
+
+	buf := make([]byte, WindowSize*channels*2)
+	samples := make([]float64, WindowSize)
+	start := time.Now()
+	blocks := 0
+
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return err
+		}
+
+		//	Mix the channels down to mono
+		for i := range samples {
+			total := 0
+			for c := 0; c < channels; c++ {
+				total += int(int16(binary.LittleEndian.Uint16(buf[(i*channels+c)*2:])))
+			}
+			samples[i] = float64(total) / float64(channels) / 32768
+		}
+
+		p.Process(samples, time.Now())
+		blocks++
+
+		if paced {
+			due := start.Add(time.Duration(blocks*WindowSize) * time.Second / time.Duration(sampleRate))
+			select {
+			case <-time.After(time.Until(due)):
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+}
+
+// readHeader checks for a WAV header and returns the format of the audio.  If there's no
+// header, the passed (raw) format is returned and nothing is consumed
+func readHeader(r *bufio.Reader, sampleRate, channels int) (int, int, error) {
+	magic, err := r.Peek(12)
+	if err != nil || string(magic[0:4]) != "RIFF" || string(magic[8:12]) != "WAVE" {
+		if channels < 1 || sampleRate < 1 {
+			return 0, 0, fmt.Errorf("raw audio needs a sample rate and channels of at least 1")
+		}
+		return sampleRate, channels, nil
+	}
+	r.Discard(12)
+
+	//	Walk the chunks until we find the audio data
+	for {
+		chunk := make([]byte, 8)
+		if _, err := io.ReadFull(r, chunk); err != nil {
+			return 0, 0, fmt.Errorf("problem reading wav header: %v", err)
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			if size < 16 || size > maxFormatSize {
+				return 0, 0, fmt.Errorf("wav format chunk has a bad size: %v", size)
+			}
+			format := make([]byte, size+size%2)
+			if _, err := io.ReadFull(r, format); err != nil {
+				return 0, 0, fmt.Errorf("problem reading wav format: %v", err)
+			}
+
+			audioFormat := binary.LittleEndian.Uint16(format[0:2])
+			channels = int(binary.LittleEndian.Uint16(format[2:4]))
+			sampleRate = int(binary.LittleEndian.Uint32(format[4:8]))
+			bits := binary.LittleEndian.Uint16(format[14:16])
+			if audioFormat != 1 || bits != 16 {
+				return 0, 0, fmt.Errorf("only 16 bit PCM wav files are supported")
+			}
+
+		case "data":
+			if channels < 1 || sampleRate < 1 {
+				return 0, 0, fmt.Errorf("wav file is missing its format")
+			}
+			return sampleRate, channels, nil
+
+		default:
+			//	Chunks are padded to an even size
+			if _, err := io.CopyN(io.Discard, r, size+size%2); err != nil {
+				return 0, 0, fmt.Errorf("problem reading wav header: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"math"
+	"net"
+	"time"
+)
+
+// WLEDMulticastGroup is the multicast address WLED sends audio sync packets to
+const WLEDMulticastGroup = "239.0.0.1"
+
+// wledPacketSize is the size of a WLED (version 2) audio sync packet
+const wledPacketSize = 44
+
+// WLEDInput listens for WLED sound reactive "audio sync" packets, so effects can react
+// to audio analyzed by a WLED microphone node on the network
+type WLEDInput struct {
+	frameStore
+	port      int
+	iface     string
+	lastPeak  bool
+	beats     int
+	lastBeat  time.Time
+	lastFrame uint8
+	received  bool
+}
+
+// NewWLEDInput creates a WLED audio sync receiver on the passed port (WLED uses 11988).
+// Iface is the network interface to join the multicast group on.  Blank means the system default
+func NewWLEDInput(port int, iface string) *WLEDInput {
+	w := WLEDInput{}
+	w.port = port
+	w.iface = iface
+	return &w
+}
+
+// Run listens for packets until the context is cancelled
+func (w *WLEDInput) Run(ctx context.Context) {
+	log.Info().Int("port", w.port).Str("interface", w.iface).Msg("Starting WLED audio sync receiver")
+
+	for {
+		err := w.listen(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Info().Msg("Stopping WLED audio sync receiver")
+			return
+		default:
+		}
+
+		log.Err(err).Msg("Problem receiving WLED audio sync packets.  Retrying")
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// listen joins the multicast group and processes packets until there's an error
+func (w *WLEDInput) listen(ctx context.Context) error {
+	var ifi *net.Interface
+	if w.iface != "" {
+		var err error
+		ifi, err = net.InterfaceByName(w.iface)
+		if err != nil {
+			return fmt.Errorf("problem finding network interface: %v", err)
+		}
+	}
+
+	conn, err := net.ListenMulticastUDP("udp4", ifi, &net.UDPAddr{IP: net.ParseIP(WLEDMulticastGroup), Port: w.port})
+	if err != nil {
+		return fmt.Errorf("problem joining multicast group: %v", err)
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 1500)
+	for {
+		//	Wake up every so often to see if we've been asked to stop
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFromUDP(buf)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+
+		if _, err := w.Process(buf[:n], time.Now()); err != nil {
+			log.Debug().Err(err).Msg("Ignoring WLED audio sync packet")
+		}
+	}
+}
+
+// Process decodes a WLED (version 2) audio sync packet and publishes the resulting frame
+func (w *WLEDInput) Process(packet []byte, now time.Time) (Frame, error) {
+	if len(packet) < wledPacketSize || string(packet[0:5]) != "00002" {
+		return Frame{}, fmt.Errorf("not a version 2 audio sync packet")
+	}
+
+	//	Packets can arrive more than once (or out of order) over multicast
+	frameCounter := packet[17]
+	if w.received && frameCounter == w.lastFrame {
+		return w.Latest(), nil
+	}
+	w.lastFrame = frameCounter
+	w.received = true
+
+	sampleRaw := math.Float32frombits(binary.LittleEndian.Uint32(packet[8:12]))
+	sampleSmth := math.Float32frombits(binary.LittleEndian.Uint32(packet[12:16]))
+	samplePeak := packet[16] != 0
+
+	frame := Frame{
+		Level: clamp(float64(sampleSmth) / 255),
+		Peak:  clamp(float64(sampleRaw) / 255),
+		Time:  now,
+	}
+
+	for i := 0; i < Bands; i++ {
+		frame.Bands[i] = float64(packet[18+i]) / 255
+	}
+
+	//	WLED holds the peak flag for a little while, so a beat is when it turns on
+	if samplePeak && !w.lastPeak && now.Sub(w.lastBeat) > beatCooldown {
+		frame.Beat = true
+		w.beats++
+		w.lastBeat = now
+	}
+	w.lastPeak = samplePeak
+	frame.Beats = w.beats
+
+	w.set(frame)
+	return frame, nil
+}
+
+// clamp keeps f in the range 0 - 1
+func clamp(f float64) float64 {
+	return math.Max(0, math.Min(1, f))
+}
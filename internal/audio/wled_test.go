@@ -0,0 +1,63 @@
+package audio_test
+
+import (
+	"encoding/binary"
+	"github.com/danesparza/fxpixel/internal/audio"
+	"math"
+	"testing"
+	"time"
+)
+
+func wledPacket(counter uint8, sample float32, peak bool, fft [16]uint8) []byte {
+	retval := make([]byte, 44)
+	copy(retval, "00002")
+	binary.LittleEndian.PutUint32(retval[8:], math.Float32bits(sample))
+	binary.LittleEndian.PutUint32(retval[12:], math.Float32bits(sample))
+	if peak {
+		retval[16] = 1
+	}
+	retval[17] = counter
+	copy(retval[18:34], fft[:])
+	return retval
+}
+
+func TestWLEDInputProcess(t *testing.T) {
+	w := audio.NewWLEDInput(11988, "")
+	now := time.Now()
+
+	fft := [16]uint8{255, 128}
+	frame, err := w.Process(wledPacket(1, 127.5, false, fft), now)
+	if err != nil {
+		t.Fatalf("Process returned an error: %v", err)
+	}
+
+	if frame.Level != 0.5 {
+		t.Errorf("Level = %v, want 0.5", frame.Level)
+	}
+	if frame.Bands[0] != 1 || frame.Bands[2] != 0 {
+		t.Errorf("Bands = %v, want the first band full and the third empty", frame.Bands)
+	}
+
+	//	The peak flag turning on is a beat
+	now = now.Add(200 * time.Millisecond)
+	frame, _ = w.Process(wledPacket(2, 200, true, fft), now)
+	if !frame.Beat || frame.Beats != 1 {
+		t.Errorf("Beat = %v (beats %v), want a beat", frame.Beat, frame.Beats)
+	}
+
+	//	... and staying on isn't another one
+	now = now.Add(200 * time.Millisecond)
+	frame, _ = w.Process(wledPacket(3, 200, true, fft), now)
+	if frame.Beat || frame.Beats != 1 {
+		t.Errorf("Beat = %v (beats %v), want no new beat", frame.Beat, frame.Beats)
+	}
+
+	if latest := w.Latest(); latest.Beats != 1 || latest.Level == 0 {
+		t.Errorf("Latest = %+v, want the last frame", latest)
+	}
+
+	//	Other packets are ignored
+	if _, err := w.Process([]byte("00001 not a v2 packet"), now); err == nil {
+		t.Errorf("Process accepted a packet that isn't version 2")
+	}
+}
@@ -0,0 +1,31 @@
+package data_test
+
+import (
+	"github.com/danesparza/fxpixel/internal/data"
+	"testing"
+)
+
+func TestCheckKeyframes(t *testing.T) {
+	tests := []struct {
+		name      string
+		keyframes []data.CircadianKeyframe
+		wantErr   bool
+	}{
+		{name: "No keyframes", keyframes: nil},
+		{name: "Keyframes", keyframes: []data.CircadianKeyframe{{Time: "07:30", Temperature: 4000, Brightness: 200}, {Time: "21:00", Temperature: 2200}}},
+		{name: "Midnight", keyframes: []data.CircadianKeyframe{{Time: "00:00", Temperature: 1900}}},
+		{name: "Not a time", keyframes: []data.CircadianKeyframe{{Time: "morning", Temperature: 4000}}, wantErr: true},
+		{name: "Hour out of range", keyframes: []data.CircadianKeyframe{{Time: "25:00", Temperature: 4000}}, wantErr: true},
+		{name: "Missing time", keyframes: []data.CircadianKeyframe{{Temperature: 4000}}, wantErr: true},
+		{name: "Missing temperature", keyframes: []data.CircadianKeyframe{{Time: "07:30", Temperature: 4000}, {Time: "12:00"}}, wantErr: true},
+		{name: "Negative temperature", keyframes: []data.CircadianKeyframe{{Time: "07:30", Temperature: -2700}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := data.CheckKeyframes(tt.keyframes); (err != nil) != tt.wantErr {
+				t.Errorf("CheckKeyframes(%+v) error = %v, wantErr %v", tt.keyframes, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The range of color temperatures (in Kelvin) a color can use
+const (
+	MinKelvin = 1000
+	MaxKelvin = 40000
+)
+
+// UnmarshalJSON reads a color either as an object with R, G, B and W fields or as
+// a color specification string (see ParseColor)
+func (c *MetaColor) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), []byte("null")) {
+		return nil
+	}
+
+	var spec string
+	if err := json.Unmarshal(b, &spec); err == nil {
+		color, err := ParseColor(spec)
+		if err != nil {
+			return err
+		}
+		*c = color
+		return nil
+	}
+
+	//	Otherwise it's the usual object.  (The alias keeps us from calling ourselves)
+	type metaColor MetaColor
+	return json.Unmarshal(b, (*metaColor)(c))
+}
+
+// ParseColor reads a color specification.  It understands:
+//
+//	"#F80", "#FF8800" or "#FF8800FF" (the last pair is white)
+//	"rgb(255, 136, 0)" or "rgbw(255, 136, 0, 255)" (channels 0 - 255, or percentages)
+//	"hsv(30, 100%, 100%)" (hue in degrees, saturation and value as percentages)
+//	"2700K" (a color temperature, 1000K - 40000K)
+//	CSS color names like "orange", and "candlelight", "warmwhite", "softwhite",
+//	"neutralwhite", "coolwhite" and "daylight" (which are color temperatures)
+func ParseColor(spec string) (MetaColor, error) {
+	s := strings.ToLower(strings.TrimSpace(spec))
+
+	var (
+		color MetaColor
+		err   error
+	)
+
+	switch {
+	case s == "":
+		err = fmt.Errorf("the color is empty")
+	case strings.HasPrefix(s, "#"):
+		color, err = parseHexColor(s[1:])
+	case strings.HasPrefix(s, "rgbw("):
+		color, err = parseRGBColor(s[len("rgbw("):], 4)
+	case strings.HasPrefix(s, "rgb("):
+		color, err = parseRGBColor(s[len("rgb("):], 3)
+	case strings.HasPrefix(s, "hsv("):
+		color, err = parseHSVColor(s[len("hsv("):])
+	case strings.HasSuffix(s, "k") && len(s) > 1 && isNumber(s[:len(s)-1]):
+		color, err = parseKelvinColor(s[:len(s)-1])
+	default:
+		name := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(s)
+		if c, found := namedColors[name]; found {
+			color = c
+		} else if k, found := namedWhites[name]; found {
+			color = KelvinColor(k)
+		} else {
+			err = fmt.Errorf("unknown color name")
+		}
+	}
+
+	if err != nil {
+		return MetaColor{}, fmt.Errorf("invalid color %q: %v", spec, err)
+	}
+	return color, nil
+}
+
+// KelvinColor approximates the color of a black body at the passed color
+// temperature (in Kelvin).  Based on Tanner Helland's curve fit:
+// https://tannerhelland.com/2012/09/18/convert-temperature-rgb-algorithm-code.html
+func KelvinColor(kelvin int) MetaColor {
+	t := float64(kelvin) / 100.0
+
+	var r, g, b float64
+
+	if t <= 66 {
+		r = 255
+		g = 99.4708025861*math.Log(t) - 161.1195681661
+	} else {
+		r = 329.698727446 * math.Pow(t-60, -0.1332047592)
+		g = 288.1221695283 * math.Pow(t-60, -0.0755148492)
+	}
+
+	switch {
+	case t >= 66:
+		b = 255
+	case t <= 19:
+		b = 0
+	default:
+		b = 138.5177312231*math.Log(t-10) - 305.0447927307
+	}
+
+	return MetaColor{
+		R: toChannel(r),
+		G: toChannel(g),
+		B: toChannel(b),
+		K: kelvin,
+	}
+}
+
+// HSVColor converts hue (0 - 360), saturation and value (0 - 1) to a color
+func HSVColor(h, s, v float64) MetaColor {
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return MetaColor{
+		R: toChannel((r + m) * 255),
+		G: toChannel((g + m) * 255),
+		B: toChannel((b + m) * 255),
+	}
+}
+
+func parseHexColor(s string) (MetaColor, error) {
+	//	#RGB is shorthand for #RRGGBB
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 && len(s) != 8 {
+		return MetaColor{}, fmt.Errorf("hex colors need 3, 6 or 8 digits")
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return MetaColor{}, fmt.Errorf("%q isn't a hex number", s)
+	}
+
+	if len(s) == 6 {
+		v <<= 8
+	}
+	return MetaColor{
+		R: int(v >> 24 & 0xff),
+		G: int(v >> 16 & 0xff),
+		B: int(v >> 8 & 0xff),
+		W: int(v & 0xff),
+	}, nil
+}
+
+func parseRGBColor(s string, channels int) (MetaColor, error) {
+	args, err := colorArgs(s, channels)
+	if err != nil {
+		return MetaColor{}, err
+	}
+
+	values := make([]int, 4)
+	for i, arg := range args {
+		f, percent, err := parseColorNumber(arg)
+		if err != nil {
+			return MetaColor{}, err
+		}
+		if percent {
+			f = f * 255 / 100
+		}
+		if f < 0 || f > 255 {
+			return MetaColor{}, fmt.Errorf("%q is out of range (0 - 255)", arg)
+		}
+		values[i] = toChannel(f)
+	}
+
+	return MetaColor{R: values[0], G: values[1], B: values[2], W: values[3]}, nil
+}
+
+func parseHSVColor(s string) (MetaColor, error) {
+	args, err := colorArgs(s, 3)
+	if err != nil {
+		return MetaColor{}, err
+	}
+
+	h, _, err := parseColorNumber(strings.TrimSuffix(args[0], "deg"))
+	if err != nil {
+		return MetaColor{}, err
+	}
+
+	sv := make([]float64, 2)
+	for i, arg := range args[1:] {
+		f, _, err := parseColorNumber(arg)
+		if err != nil {
+			return MetaColor{}, err
+		}
+		if f < 0 || f > 100 {
+			return MetaColor{}, fmt.Errorf("%q is out of range (0%% - 100%%)", arg)
+		}
+		sv[i] = f / 100
+	}
+
+	//	Wrap the hue around the color wheel
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+
+	return HSVColor(h, sv[0], sv[1]), nil
+}
+
+func parseKelvinColor(s string) (MetaColor, error) {
+	k, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(k) || math.IsInf(k, 0) {
+		return MetaColor{}, fmt.Errorf("%q isn't a number", s)
+	}
+	if k < MinKelvin || k > MaxKelvin {
+		return MetaColor{}, fmt.Errorf("color temperatures need to be between %vK and %vK", MinKelvin, MaxKelvin)
+	}
+
+	return KelvinColor(int(math.Round(k))), nil
+}
+
+// colorArgs splits the arguments of a color function (after the opening bracket)
+func colorArgs(s string, count int) ([]string, error) {
+	s, found := strings.CutSuffix(s, ")")
+	if !found {
+		return nil, fmt.Errorf("missing ')'")
+	}
+
+	args := strings.Split(s, ",")
+	if len(args) != count {
+		return nil, fmt.Errorf("expected %v values, found %v", count, len(args))
+	}
+	for i := range args {
+		args[i] = strings.TrimSpace(args[i])
+	}
+	return args, nil
+}
+
+// parseColorNumber parses a number, which may be a percentage
+func parseColorNumber(s string) (float64, bool, error) {
+	s, percent := strings.CutSuffix(s, "%")
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false, fmt.Errorf("%q isn't a number", s)
+	}
+	return f, percent, nil
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// toChannel rounds and clamps a color channel value to 0 - 255
+func toChannel(f float64) int {
+	if f < 0 || math.IsNaN(f) {
+		return 0
+	}
+	if f > 255 {
+		return 255
+	}
+	return int(math.Round(f))
+}
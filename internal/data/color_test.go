@@ -0,0 +1,67 @@
+package data_test
+
+import (
+	"encoding/json"
+	"github.com/danesparza/fxpixel/internal/data"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		spec    string
+		want    data.MetaColor
+		wantErr bool
+	}{
+		{name: "Hex color", spec: "#FF8800", want: data.MetaColor{R: 255, G: 136}},
+		{name: "Short hex color", spec: "#f80", want: data.MetaColor{R: 255, G: 136}},
+		{name: "Hex color with white", spec: "#FF880040", want: data.MetaColor{R: 255, G: 136, W: 64}},
+		{name: "RGB color", spec: "rgb(255, 136, 0)", want: data.MetaColor{R: 255, G: 136}},
+		{name: "RGBW color with percentages", spec: "rgbw(100%, 0, 0, 50%)", want: data.MetaColor{R: 255, W: 128}},
+		{name: "HSV color", spec: "hsv(30,100%,100%)", want: data.MetaColor{R: 255, G: 128}},
+		{name: "HSV hue wraps around", spec: "hsv(-240, 100, 50)", want: data.MetaColor{G: 128}},
+		{name: "CSS named color", spec: "Orange", want: data.MetaColor{R: 255, G: 165}},
+		{name: "Named white", spec: "warm white", want: data.MetaColor{R: 255, G: 167, B: 87, K: 2700}},
+		{name: "Color temperature", spec: "2700K", want: data.MetaColor{R: 255, G: 167, B: 87, K: 2700}},
+		{name: "Daylight color temperature", spec: "6500k", want: data.MetaColor{R: 255, G: 254, B: 250, K: 6500}},
+		{name: "Unknown name", spec: "blurple", wantErr: true},
+		{name: "Bad hex color", spec: "#FF88", wantErr: true},
+		{name: "Missing HSV value", spec: "hsv(30, 100%)", wantErr: true},
+		{name: "Color temperature out of range", spec: "100K", wantErr: true},
+		{name: "Color temperature not a number", spec: "nanK", wantErr: true},
+		{name: "Color temperature infinite", spec: "infK", wantErr: true},
+		{name: "Empty color", spec: " ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := data.ParseColor(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColor(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseColor(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMetaColorFromJSON(t *testing.T) {
+	meta := data.FadeMeta{}
+	err := json.Unmarshal([]byte(`{"color": "#0000FF", "sequence": [{"R": 255}, "lime"]}`), &meta)
+	if err != nil {
+		t.Fatalf("Unmarshal error = %v", err)
+	}
+
+	if meta.Color != (data.MetaColor{B: 255}) {
+		t.Errorf("Color = %+v, want blue", meta.Color)
+	}
+	if len(meta.Sequence) != 2 || meta.Sequence[0] != (data.MetaColor{R: 255}) || meta.Sequence[1] != (data.MetaColor{G: 255}) {
+		t.Errorf("Sequence = %+v, want red and lime", meta.Sequence)
+	}
+
+	if err := json.Unmarshal([]byte(`{"color": "nope"}`), &meta); err == nil {
+		t.Errorf("Unmarshal of an unknown color name didn't return an error")
+	}
+}
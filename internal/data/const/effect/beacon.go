@@ -0,0 +1,5 @@
+package effect
+
+const BEACON_POLICE = "police"
+const BEACON_AMBER = "amber"
+const BEACON_WIGWAG = "wig-wag"
@@ -0,0 +1,4 @@
+package effect
+
+const CLOCK_24H = "24h"
+const CLOCK_12H = "12h"
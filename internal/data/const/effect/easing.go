@@ -0,0 +1,35 @@
+package effect
+
+const EASE_LINEAR = "linear"
+
+// Shorthand curves (like CSS)
+const EASE_IN = "ease-in"
+const EASE_OUT = "ease-out"
+const EASE_IN_OUT = "ease-in-out"
+
+const EASE_IN_QUAD = "ease-in-quad"
+const EASE_OUT_QUAD = "ease-out-quad"
+const EASE_IN_OUT_QUAD = "ease-in-out-quad"
+
+const EASE_IN_CUBIC = "ease-in-cubic"
+const EASE_OUT_CUBIC = "ease-out-cubic"
+const EASE_IN_OUT_CUBIC = "ease-in-out-cubic"
+
+const EASE_IN_SINE = "ease-in-sine"
+const EASE_OUT_SINE = "ease-out-sine"
+const EASE_IN_OUT_SINE = "ease-in-out-sine"
+
+const EASE_IN_EXPO = "ease-in-expo"
+const EASE_OUT_EXPO = "ease-out-expo"
+const EASE_IN_OUT_EXPO = "ease-in-out-expo"
+
+const EASE_IN_BOUNCE = "ease-in-bounce"
+const EASE_OUT_BOUNCE = "ease-out-bounce"
+const EASE_IN_OUT_BOUNCE = "ease-in-out-bounce"
+
+const EASE_IN_ELASTIC = "ease-in-elastic"
+const EASE_OUT_ELASTIC = "ease-out-elastic"
+const EASE_IN_OUT_ELASTIC = "ease-in-out-elastic"
+
+// EASE_CUBIC_BEZIER is a custom curve, written like CSS: cubic-bezier(x1, y1, x2, y2)
+const EASE_CUBIC_BEZIER = "cubic-bezier"
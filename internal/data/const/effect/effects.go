@@ -15,6 +15,25 @@ const (
 	Zip
 	KnightRider
 	Lightning
+	Plasma
+	Noise
+	Candle
+	BouncingBalls
+	Strobe
+	Beacon
+	Image
+	Gif
+	Text
+	Clock
+	Countdown
+	VUMeter
+	Spectrum
+	BeatPulse
+	Formula
+	Script
+	Sunrise
+	Sunset
+	Circadian
 )
 
 // FromString converts a string representation of an effect type to a EffectType
@@ -38,6 +57,44 @@ func FromString(stringtype string) EffectType {
 		retval = KnightRider
 	case "lightning":
 		retval = Lightning
+	case "plasma":
+		retval = Plasma
+	case "noise":
+		retval = Noise
+	case "candle":
+		retval = Candle
+	case "bouncing-balls", "bouncingballs":
+		retval = BouncingBalls
+	case "strobe":
+		retval = Strobe
+	case "beacon":
+		retval = Beacon
+	case "image":
+		retval = Image
+	case "gif":
+		retval = Gif
+	case "text":
+		retval = Text
+	case "clock":
+		retval = Clock
+	case "countdown":
+		retval = Countdown
+	case "vu-meter", "vumeter":
+		retval = VUMeter
+	case "spectrum":
+		retval = Spectrum
+	case "beat-pulse", "beatpulse":
+		retval = BeatPulse
+	case "formula":
+		retval = Formula
+	case "script":
+		retval = Script
+	case "sunrise":
+		retval = Sunrise
+	case "sunset":
+		retval = Sunset
+	case "circadian":
+		retval = Circadian
 	}
 
 	return retval
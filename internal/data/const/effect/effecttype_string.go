@@ -17,15 +17,35 @@ func _() {
 	_ = x[Zip-6]
 	_ = x[KnightRider-7]
 	_ = x[Lightning-8]
+	_ = x[Plasma-9]
+	_ = x[Noise-10]
+	_ = x[Candle-11]
+	_ = x[BouncingBalls-12]
+	_ = x[Strobe-13]
+	_ = x[Beacon-14]
+	_ = x[Image-15]
+	_ = x[Gif-16]
+	_ = x[Text-17]
+	_ = x[Clock-18]
+	_ = x[Countdown-19]
+	_ = x[VUMeter-20]
+	_ = x[Spectrum-21]
+	_ = x[BeatPulse-22]
+	_ = x[Formula-23]
+	_ = x[Script-24]
+	_ = x[Sunrise-25]
+	_ = x[Sunset-26]
+	_ = x[Circadian-27]
 }
 
-const _EffectType_name = "UnknownSolidFadeGradientSequenceRainbowZipKnightRiderLightning"
+const _EffectType_name = "UnknownSolidFadeGradientSequenceRainbowZipKnightRiderLightningPlasmaNoiseCandleBouncingBallsStrobeBeaconImageGifTextClockCountdownVUMeterSpectrumBeatPulseFormulaScriptSunriseSunsetCircadian"
 
-var _EffectType_index = [...]uint8{0, 7, 12, 16, 24, 32, 39, 42, 53, 62}
+var _EffectType_index = [...]uint8{0, 7, 12, 16, 24, 32, 39, 42, 53, 62, 68, 73, 79, 92, 98, 104, 109, 112, 116, 121, 130, 137, 145, 154, 161, 167, 174, 180, 189}
 
 func (i EffectType) String() string {
-	if i < 0 || i >= EffectType(len(_EffectType_index)-1) {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_EffectType_index)-1 {
 		return "EffectType(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
-	return _EffectType_name[_EffectType_index[i]:_EffectType_index[i+1]]
+	return _EffectType_name[_EffectType_index[idx]:_EffectType_index[idx+1]]
 }
@@ -0,0 +1,5 @@
+package effect
+
+// FADE_FROM_CURRENT starts a fade from what the LEDs are actually showing
+// (even if that was left by the previous timeline)
+const FADE_FROM_CURRENT = "current"
@@ -0,0 +1,4 @@
+package effect
+
+const GIF_FIT = "fit"
+const GIF_FILL = "fill"
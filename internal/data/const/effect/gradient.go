@@ -0,0 +1,5 @@
+package effect
+
+const COLORSPACE_RGB = "rgb"
+const COLORSPACE_HSV = "hsv"
+const COLORSPACE_OKLAB = "oklab"
@@ -0,0 +1,9 @@
+package effect
+
+const FONT_5X7 = "5x7"
+const FONT_8X8 = "8x8"
+
+const TEXT_LEFT = "left"
+const TEXT_RIGHT = "right"
+const TEXT_UP = "up"
+const TEXT_DOWN = "down"
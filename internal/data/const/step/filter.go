@@ -0,0 +1,11 @@
+package step
+
+const FILTER_HUE_SHIFT = "hue-shift"
+const FILTER_SATURATION = "saturation"
+const FILTER_BRIGHTNESS = "brightness"
+const FILTER_BLUR = "blur"
+const FILTER_TRAILS = "trails"
+const FILTER_POSTERIZE = "posterize"
+const FILTER_INVERT = "invert"
+const FILTER_SPARKLE = "sparkle"
+const FILTER_TINT = "tint"
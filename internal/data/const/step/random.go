@@ -0,0 +1,7 @@
+package step
+
+const RANDOM_HUE = "hue"
+const RANDOM_COLOR = "color"
+const RANDOM_WHITE = "white"
+
+const RANDOM_PER_PLAY = "play"
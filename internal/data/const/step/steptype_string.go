@@ -14,15 +14,17 @@ func _() {
 	_ = x[RandomSleep-3]
 	_ = x[Trigger-4]
 	_ = x[Loop-5]
+	_ = x[Label-6]
 }
 
-const _StepType_name = "UnknownEffectSleepRandomSleepTriggerLoop"
+const _StepType_name = "UnknownEffectSleepRandomSleepTriggerLoopLabel"
 
-var _StepType_index = [...]uint8{0, 7, 13, 18, 29, 36, 40}
+var _StepType_index = [...]uint8{0, 7, 13, 18, 29, 36, 40, 45}
 
 func (i StepType) String() string {
-	if i < 0 || i >= StepType(len(_StepType_index)-1) {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_StepType_index)-1 {
 		return "StepType(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
-	return _StepType_name[_StepType_index[i]:_StepType_index[i+1]]
+	return _StepType_name[_StepType_index[idx]:_StepType_index[idx+1]]
 }
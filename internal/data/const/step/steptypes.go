@@ -12,6 +12,7 @@ const (
 	RandomSleep
 	Trigger
 	Loop
+	Label
 )
 
 // FromString converts a string representation of a step type to a StepType
@@ -29,6 +30,8 @@ func FromString(stringtype string) StepType {
 		retval = Trigger
 	case "loop":
 		retval = Loop
+	case "label":
+		retval = Label
 	}
 
 	return retval
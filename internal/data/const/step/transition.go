@@ -0,0 +1,5 @@
+package step
+
+const TRANSITION_CROSSFADE = "crossfade"
+const TRANSITION_WIPE = "wipe"
+const TRANSITION_DISSOLVE = "dissolve"
@@ -0,0 +1,52 @@
+package data
+
+import (
+	"fmt"
+	"github.com/danesparza/fxpixel/internal/data/const/step"
+	"strings"
+)
+
+// Limits on filter amounts, so the filters can keep up with the frame rate
+const (
+	MaxFilterBlur    = 32   // Widest blur radius (in pixels)
+	MaxFilterSparkle = 1000 // Most sparkles a second
+)
+
+// CheckFilters makes sure the filters are ones we know, with amounts that make sense
+func CheckFilters(filters []StepFilter) error {
+	for i, f := range filters {
+		amount := 0.0
+		if f.Amount != nil {
+			amount = *f.Amount
+		}
+
+		switch strings.ToLower(f.Type) {
+		case step.FILTER_HUE_SHIFT, step.FILTER_INVERT:
+		case step.FILTER_TINT:
+			if f.Color == nil {
+				return fmt.Errorf("filter %v: tint needs a color", i+1)
+			}
+			if amount < 0 {
+				return fmt.Errorf("filter %v: the tint amount can't be negative", i+1)
+			}
+		case step.FILTER_SATURATION, step.FILTER_BRIGHTNESS, step.FILTER_BLUR, step.FILTER_TRAILS, step.FILTER_SPARKLE:
+			if amount < 0 {
+				return fmt.Errorf("filter %v: the %v amount can't be negative", i+1, f.Type)
+			}
+			if strings.EqualFold(f.Type, step.FILTER_BLUR) && amount > MaxFilterBlur {
+				return fmt.Errorf("filter %v: the blur radius can't be more than %v", i+1, MaxFilterBlur)
+			}
+			if strings.EqualFold(f.Type, step.FILTER_SPARKLE) && amount > MaxFilterSparkle {
+				return fmt.Errorf("filter %v: there can't be more than %v sparkles a second", i+1, MaxFilterSparkle)
+			}
+		case step.FILTER_POSTERIZE:
+			if f.Amount != nil && amount < 2 {
+				return fmt.Errorf("filter %v: posterize needs at least 2 levels", i+1)
+			}
+		default:
+			return fmt.Errorf("filter %v: unknown filter %q", i+1, f.Type)
+		}
+	}
+
+	return nil
+}
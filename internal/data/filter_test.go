@@ -0,0 +1,37 @@
+package data_test
+
+import (
+	"encoding/json"
+	"github.com/danesparza/fxpixel/internal/data"
+	"testing"
+)
+
+func TestCheckFilters(t *testing.T) {
+	tests := []struct {
+		name    string
+		filters string
+		wantErr bool
+	}{
+		{name: "Chain", filters: `[{"type": "blur", "amount": 2}, {"type": "Hue-Shift", "speed": 30}, {"type": "sparkle"}]`},
+		{name: "Tint", filters: `[{"type": "tint", "color": "orange", "amount": 0.3}]`},
+		{name: "Tint without a color", filters: `[{"type": "tint"}]`, wantErr: true},
+		{name: "Unknown filter", filters: `[{"type": "wobble"}]`, wantErr: true},
+		{name: "Negative amount", filters: `[{"type": "trails", "amount": -100}]`, wantErr: true},
+		{name: "Too few levels", filters: `[{"type": "posterize", "amount": 1}]`, wantErr: true},
+		{name: "Blur too wide", filters: `[{"type": "blur", "amount": 500}]`, wantErr: true},
+		{name: "Too many sparkles", filters: `[{"type": "sparkle", "amount": 1e12}]`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters := []data.StepFilter{}
+			if err := json.Unmarshal([]byte(tt.filters), &filters); err != nil {
+				t.Fatalf("Unmarshal error = %v", err)
+			}
+
+			if err := data.CheckFilters(filters); (err != nil) != tt.wantErr {
+				t.Errorf("CheckFilters error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+func (a appDataService) AddImage(ctx context.Context, source Image) (Image, error) {
+	//	Our return item
+	retval := Image{
+		ID:       source.ID,
+		Name:     source.Name,
+		Created:  time.Now(),
+		FileName: source.FileName,
+		Width:    source.Width,
+		Height:   source.Height,
+	}
+
+	query := `insert into image(id, name, created, file_name, width, height)
+				values($1, $2, $3, $4, $5, $6);`
+
+	stmt, err := a.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return retval, err
+	}
+
+	_, err = stmt.ExecContext(ctx, retval.ID, retval.Name, retval.Created.Format(time.DateTime), retval.FileName, retval.Width, retval.Height)
+	if err != nil {
+		return retval, fmt.Errorf("problem adding image: %v", err)
+	}
+
+	return retval, nil
+}
+
+func (a appDataService) GetImage(ctx context.Context, id string) (Image, error) {
+	//	Our return item
+	retval := Image{}
+
+	query := `select id, name, created, file_name, width, height from image where id = $1;`
+
+	stmt, err := a.DB.PreparexContext(ctx, query)
+	if err != nil {
+		return retval, err
+	}
+
+	rows, err := stmt.QueryxContext(ctx, id)
+	if err != nil {
+		return retval, err
+	}
+
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Err(closeErr).Msg("unable to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		retval, err = scanImage(rows.Scan)
+		if err != nil {
+			return retval, err
+		}
+	}
+
+	if retval.ID == "" {
+		return retval, fmt.Errorf("image not found: %v", id)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+func (a appDataService) GetAllImages(ctx context.Context) ([]Image, error) {
+	//	Our return item
+	retval := []Image{}
+
+	query := `select id, name, created, file_name, width, height from image order by name;`
+
+	stmt, err := a.DB.PreparexContext(ctx, query)
+	if err != nil {
+		return retval, err
+	}
+
+	rows, err := stmt.QueryxContext(ctx)
+	if err != nil {
+		return retval, err
+	}
+
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Err(closeErr).Msg("unable to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		item, err := scanImage(rows.Scan)
+		if err != nil {
+			return retval, err
+		}
+
+		retval = append(retval, item)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+func (a appDataService) DeleteImage(ctx context.Context, id string) error {
+
+	query := `delete from image where id = $1;`
+
+	stmt, err := a.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("problem preparing context: %v", err)
+	}
+
+	_, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("problem deleting image: %v", err)
+	}
+
+	return nil
+}
+
+// scanImage reads a single image row using the passed scan function
+func scanImage(scan func(dest ...any) error) (Image, error) {
+	retval := Image{}
+	createTime := ""
+
+	if err := scan(&retval.ID, &retval.Name, &createTime, &retval.FileName, &retval.Width, &retval.Height); err != nil {
+		return retval, fmt.Errorf("problem reading into struct: %v", err)
+	}
+
+	//	Parse the time:
+	parsedDate, err := time.Parse(time.DateTime, createTime)
+	if err != nil {
+		return retval, fmt.Errorf("problem parsing create date: %v", err)
+	}
+	retval.Created = parsedDate
+
+	return retval, nil
+}
@@ -0,0 +1,81 @@
+package data
+
+import (
+	"fmt"
+	"github.com/danesparza/fxpixel/internal/data/const/step"
+	"strings"
+)
+
+// LoopTarget returns the index of the step the loop at index i goes back to.  Loops can
+// only go back to an earlier step (or label), and have to loop over at least one step
+// that does something
+func LoopTarget(steps []TimelineStep, i int) (int, error) {
+	meta, _ := steps[i].MetaInfo.(LoopMeta)
+
+	target := 0
+	switch {
+	case meta.Label != "":
+		target = -1
+		for n := i - 1; n >= 0; n-- {
+			label, _ := steps[n].MetaInfo.(LabelMeta)
+			if steps[n].Type == step.Label && strings.EqualFold(label.Name, meta.Label) {
+				target = n
+				break
+			}
+		}
+		if target < 0 {
+			return 0, fmt.Errorf("there isn't a label %q before the loop", meta.Label)
+		}
+
+	case meta.Step > 0:
+		if meta.Step > i {
+			return 0, fmt.Errorf("a loop can only go back to an earlier step (step %v isn't before it)", meta.Step)
+		}
+		target = meta.Step - 1
+
+	case meta.Step < 0:
+		return 0, fmt.Errorf("there isn't a step %v", meta.Step)
+	}
+
+	//	Loops over nothing but labels (and other loops) would spin without doing anything
+	for n := target; n < i; n++ {
+		if steps[n].Type != step.Label && steps[n].Type != step.Loop {
+			return target, nil
+		}
+	}
+
+	return 0, fmt.Errorf("there aren't any steps to loop")
+}
+
+// CheckLoops makes sure each loop goes back to a step before it, and that loops are
+// properly nested (a loop that starts inside another one has to end inside it too)
+func CheckLoops(steps []TimelineStep) error {
+	type span struct{ start, end int }
+	loops := []span{}
+
+	for i, s := range steps {
+		if s.Type != step.Loop {
+			continue
+		}
+
+		if meta, _ := s.MetaInfo.(LoopMeta); meta.Count < 0 {
+			return fmt.Errorf("the loop at step %v can't have a negative count", i+1)
+		}
+
+		target, err := LoopTarget(steps, i)
+		if err != nil {
+			return fmt.Errorf("the loop at step %v: %v", i+1, err)
+		}
+
+		//	Any earlier loop either ends before this one starts, or is inside it
+		for _, l := range loops {
+			if l.end >= target && l.start < target {
+				return fmt.Errorf("the loops at steps %v and %v overlap", l.end+1, i+1)
+			}
+		}
+
+		loops = append(loops, span{start: target, end: i})
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package data_test
+
+import (
+	"github.com/danesparza/fxpixel/internal/data"
+	"github.com/danesparza/fxpixel/internal/data/const/step"
+	"testing"
+)
+
+func TestLoopTarget(t *testing.T) {
+	steps := []data.TimelineStep{
+		{Type: step.Effect},
+		{Type: step.Label, MetaInfo: data.LabelMeta{Name: "flash"}},
+		{Type: step.Effect},
+		{Type: step.Loop, MetaInfo: data.LoopMeta{Count: 5, Label: "Flash"}},
+		{Type: step.Effect},
+		{Type: step.Loop, MetaInfo: data.LoopMeta{Step: 5}},
+		{Type: step.Loop},
+		{Type: step.Loop, MetaInfo: data.LoopMeta{Label: "idle"}},
+		{Type: step.Loop, MetaInfo: data.LoopMeta{Step: 10}},
+	}
+
+	tests := []struct {
+		name    string
+		loop    int
+		want    int
+		wantErr bool
+	}{
+		{name: "Label", loop: 3, want: 1},
+		{name: "Step number", loop: 5, want: 4},
+		{name: "Start of the timeline", loop: 6, want: 0},
+		{name: "Unknown label", loop: 7, wantErr: true},
+		{name: "Later step", loop: 8, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := data.LoopTarget(steps, tt.loop)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoopTarget error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("LoopTarget = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckLoops(t *testing.T) {
+	effect := data.TimelineStep{Type: step.Effect}
+	label := data.TimelineStep{Type: step.Label, MetaInfo: data.LabelMeta{Name: "top"}}
+	loop := func(meta data.LoopMeta) data.TimelineStep {
+		return data.TimelineStep{Type: step.Loop, MetaInfo: meta}
+	}
+
+	tests := []struct {
+		name    string
+		steps   []data.TimelineStep
+		wantErr bool
+	}{
+		{name: "Nested", steps: []data.TimelineStep{effect, effect, loop(data.LoopMeta{Count: 3, Step: 2}), effect, loop(data.LoopMeta{Count: 2})}},
+		{name: "One after another", steps: []data.TimelineStep{effect, loop(data.LoopMeta{Count: 2}), effect, loop(data.LoopMeta{Step: 3})}},
+		{name: "Overlapping", steps: []data.TimelineStep{effect, effect, loop(data.LoopMeta{Count: 2}), effect, loop(data.LoopMeta{Count: 2, Step: 2})}, wantErr: true},
+		{name: "Negative count", steps: []data.TimelineStep{effect, loop(data.LoopMeta{Count: -1})}, wantErr: true},
+		{name: "Nothing to loop", steps: []data.TimelineStep{loop(data.LoopMeta{})}, wantErr: true},
+		{name: "Only a label to loop", steps: []data.TimelineStep{effect, label, loop(data.LoopMeta{Label: "top"})}, wantErr: true},
+		{name: "Only loops to loop", steps: []data.TimelineStep{label, effect, loop(data.LoopMeta{Count: 2, Label: "top"}), loop(data.LoopMeta{Step: 3})}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := data.CheckLoops(tt.steps); (err != nil) != tt.wantErr {
+				t.Errorf("CheckLoops error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
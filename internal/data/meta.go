@@ -0,0 +1,59 @@
+package data
+
+import (
+	"fmt"
+	"github.com/danesparza/fxpixel/internal/data/const/effect"
+	"github.com/danesparza/fxpixel/internal/formula"
+	"strings"
+	"time"
+)
+
+// MaxNoiseOctaves is the most layers of detail noise and plasma effects can add
+const MaxNoiseOctaves = 8
+
+// The variables a formula can read (inputs) and set to color the pixel (outputs)
+var (
+	formulaInputs  = []string{"index", "x", "y", "t", "count", "width", "height", "level", "beat"}
+	formulaOutputs = []string{"h", "s", "v", "r", "g", "b", "w"}
+)
+
+// FormulaVariables returns the variables the formula effect provides (inputs, then outputs)
+func FormulaVariables() []string {
+	return append(append([]string{}, formulaInputs...), formulaOutputs...)
+}
+
+// CheckMeta makes sure meta info that can't be checked as it's read (like formulas,
+// circadian keyframes, beacon presets and noise octaves) will work when the step plays
+func CheckMeta(meta any) error {
+	switch m := meta.(type) {
+	case FormulaMeta:
+		_, err := formula.Compile(m.Formula, FormulaVariables()...)
+		return err
+	case CircadianMeta:
+		return CheckKeyframes(m.Keyframes)
+	case BeaconMeta:
+		switch strings.ToLower(m.Preset) {
+		case "", effect.BEACON_POLICE, effect.BEACON_AMBER, effect.BEACON_WIGWAG:
+		default:
+			return fmt.Errorf("unknown beacon preset %q (use police, amber or wig-wag)", m.Preset)
+		}
+	case NoiseMeta:
+		if m.Octaves < 0 || m.Octaves > MaxNoiseOctaves {
+			return fmt.Errorf("octaves has to be between 1 and %v", MaxNoiseOctaves)
+		}
+	}
+	return nil
+}
+
+// CheckKeyframes makes sure each circadian keyframe has a time of day and a color temperature
+func CheckKeyframes(keyframes []CircadianKeyframe) error {
+	for _, k := range keyframes {
+		if _, err := time.Parse("15:04", k.Time); err != nil {
+			return fmt.Errorf("the keyframe time %q isn't a time of day (like 07:30)", k.Time)
+		}
+		if k.Temperature <= 0 {
+			return fmt.Errorf("the keyframe at %v needs a color temperature", k.Time)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package data_test
+
+import (
+	"github.com/danesparza/fxpixel/internal/data"
+	"testing"
+)
+
+func TestCheckMeta(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    any
+		wantErr bool
+	}{
+		{name: "No meta", meta: nil},
+		{name: "Meta without checks", meta: data.SolidMeta{}},
+		{name: "Formula", meta: data.FormulaMeta{Formula: "h = frac(index / count + t); v = wave(t)"}},
+		{name: "Formula with random", meta: data.FormulaMeta{Formula: "v = random()"}},
+		{name: "Formula syntax error", meta: data.FormulaMeta{Formula: "h = (index"}, wantErr: true},
+		{name: "Formula unknown variable", meta: data.FormulaMeta{Formula: "h = speed"}, wantErr: true},
+		{name: "Circadian", meta: data.CircadianMeta{Keyframes: []data.CircadianKeyframe{{Time: "07:00", Temperature: 4000}}}},
+		{name: "Circadian bad keyframe", meta: data.CircadianMeta{Keyframes: []data.CircadianKeyframe{{Time: "7am", Temperature: 4000}}}, wantErr: true},
+		{name: "Beacon default", meta: data.BeaconMeta{}},
+		{name: "Beacon preset", meta: data.BeaconMeta{Preset: "Wig-Wag"}},
+		{name: "Beacon unknown preset", meta: data.BeaconMeta{Preset: "disco"}, wantErr: true},
+		{name: "Noise default octaves", meta: data.NoiseMeta{}},
+		{name: "Noise most octaves", meta: data.NoiseMeta{Octaves: data.MaxNoiseOctaves}},
+		{name: "Noise too many octaves", meta: data.NoiseMeta{Octaves: data.MaxNoiseOctaves + 1}, wantErr: true},
+		{name: "Noise negative octaves", meta: data.NoiseMeta{Octaves: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := data.CheckMeta(tt.meta); (err != nil) != tt.wantErr {
+				t.Errorf("CheckMeta(%+v) error = %v, wantErr %v", tt.meta, err, tt.wantErr)
+			}
+		})
+	}
+}
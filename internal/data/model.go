@@ -2,6 +2,7 @@ package data
 
 import (
 	"database/sql"
+	"encoding/json"
 	"github.com/danesparza/fxpixel/internal/data/const/effect"
 	"github.com/danesparza/fxpixel/internal/data/const/step"
 	"time"
@@ -9,39 +10,97 @@ import (
 
 // SystemConfig represents the system configuration information
 type SystemConfig struct {
-	GPIO           int    `json:"gpio"`
-	LEDs           int    `json:"leds"`
-	PixelOrder     string `json:"pixel_order"`
-	NumberOfColors int    `json:"number_of_colors"`
+	GPIO             int    `json:"gpio"`
+	LEDs             int    `json:"leds"`
+	PixelOrder       string `json:"pixel_order"`
+	NumberOfColors   int    `json:"number_of_colors"`
+	MatrixWidth      int    `json:"matrix_width"`      // Width of the LED matrix.  0 means the LEDs are a plain strip
+	MatrixHeight     int    `json:"matrix_height"`     // Height of the LED matrix
+	MatrixSerpentine bool   `json:"matrix_serpentine"` // Every other row of the matrix runs in the opposite direction
 }
 
 // Timeline represents a series of event frames to be shown in order
 type Timeline struct {
-	ID      string         `json:"id"`             // Unique Timeline ID
-	Enabled bool           `json:"enabled"`        // Timeline enabled or not
-	Created time.Time      `json:"created"`        // Timeline create time
-	Name    string         `json:"name"`           // Timeline name
-	GPIO    sql.NullInt32  `json:"gpio,omitempty"` // The GPIO device to play the timeline on.  Optional.  If not set, uses the default
-	Steps   []TimelineStep `json:"steps"`          // Steps for the timeline
-	Tags    []string       `json:"tags"`           // List of Tags to associate with this timeline
+	ID      string         `json:"id"`                // Unique Timeline ID
+	Enabled bool           `json:"enabled"`           // Timeline enabled or not
+	Created time.Time      `json:"created"`           // Timeline create time
+	Name    string         `json:"name"`              // Timeline name
+	GPIO    sql.NullInt32  `json:"gpio,omitempty"`    // The GPIO device to play the timeline on.  Optional.  If not set, uses the default
+	Steps   []TimelineStep `json:"steps"`             // Steps for the timeline
+	Tags    []string       `json:"tags"`              // List of Tags to associate with this timeline
+	Filters []StepFilter   `json:"filters,omitempty"` // Filters for every effect in the timeline (after the step's own filters)
 }
 
 // TimelineStep represents a single step in a timeline
 type TimelineStep struct {
-	ID       string            `json:"id"`                  // The timeline step id
-	Type     step.StepType     `json:"type"`                // Timeline frame type (effect/sleep/trigger/loop)
-	Effect   effect.EffectType `json:"effect,omitempty"`    // The Effect type (if Type=effect)
-	Leds     sql.NullString    `json:"leds,omitempty"`      // Leds to use for the scene (optional) If not set and is required for the type, defaults to entire strip
-	Time     sql.NullInt32     `json:"time,omitempty"`      // Time (in milliseconds).  Some things (like trigger) don't require time
-	MetaInfo any               `json:"meta-info,omitempty"` // Additional information required for specific types
-	Number   int               `json:"number"`              // The step number (ordinal position in the timeline)
+	ID         string                 `json:"id"`                   // The timeline step id
+	Type       step.StepType          `json:"type"`                 // Timeline frame type (effect/sleep/trigger/loop/label)
+	Effect     effect.EffectType      `json:"effect,omitempty"`     // The Effect type (if Type=effect)
+	Leds       sql.NullString         `json:"leds,omitempty"`       // Leds to use for the scene (optional) If not set and is required for the type, defaults to entire strip
+	Time       sql.NullInt32          `json:"time,omitempty"`       // Time (in milliseconds).  Some things (like trigger) don't require time
+	MetaInfo   any                    `json:"meta-info,omitempty"`  // Additional information required for specific types
+	Number     int                    `json:"number"`               // The step number (ordinal position in the timeline)
+	Transition *StepTransition        `json:"transition,omitempty"` // How to blend from the previous step into this one (optional)
+	Random     map[string]RandomValue `json:"random,omitempty"`     // Meta values that are picked at random, by their path in the meta info (like 'bursts' or 'colors.2')
+	Filters    []StepFilter           `json:"filters,omitempty"`    // Filters run over the frame after the effect draws it (in order)
 }
 
+// StepTransition describes how a step blends in from whatever was showing before it
+type StepTransition struct {
+	Type     string `json:"type"`     // crossfade, wipe or dissolve
+	Duration int    `json:"duration"` // Time (in milliseconds) the transition takes
+	Easing   string `json:"easing"`   // Easing curve for the transition
+}
+
+// StepFilter describes a filter that's run over each frame after the effect draws it
+type StepFilter struct {
+	Type   string     `json:"type"`             // hue-shift, saturation, brightness, blur, trails, posterize, invert, sparkle or tint
+	Amount *float64   `json:"amount,omitempty"` // How strong the filter is.  What it means depends on the type
+	Speed  float64    `json:"speed,omitempty"`  // How fast the hue shifts (in degrees a second)
+	Color  *MetaColor `json:"color,omitempty"`  // The sparkle or tint color
+}
+
+// RandomValue describes a meta value that's picked at random when the step plays
+type RandomValue struct {
+	Min        *float64          `json:"min,omitempty"`         // Min and Max pick a number in this range (inclusive for whole numbers)
+	Max        *float64          `json:"max,omitempty"`         // See Min
+	Random     string            `json:"random,omitempty"`      // Random picks a color: hue, color or white
+	RandomFrom []json.RawMessage `json:"random-from,omitempty"` // RandomFrom picks one of these values
+	Per        string            `json:"per,omitempty"`         // Per can be 'play' to pick once each time the timeline plays.  Otherwise a new value is picked each time the step runs
+}
+
+// Palette represents a named set of colors that effects can use
+type Palette struct {
+	ID      string      `json:"id"`      // Unique palette id
+	Name    string      `json:"name"`    // Palette name.  Effects reference the palette by this name
+	Created time.Time   `json:"created"` // Palette create time
+	Stops   []ColorStop `json:"stops"`   // Color stops for the palette
+}
+
+// Image represents an uploaded image in the image store
+type Image struct {
+	ID       string    `json:"id"`        // Unique image id
+	Name     string    `json:"name"`      // Image name.  Effects reference the image by this name (or id)
+	Created  time.Time `json:"created"`   // Image upload time
+	FileName string    `json:"file_name"` // File name in the image store
+	Width    int       `json:"width"`     // Width of the image in pixels
+	Height   int       `json:"height"`    // Height of the image in pixels
+}
+
+// ColorStop represents a color at a position in a palette
+type ColorStop struct {
+	Position float64   `json:"position"` // Position (0 - 1) of the color
+	Color    MetaColor `json:"color"`    // The color at this position
+}
+
+// MetaColor is a color.  In JSON it can also be a color specification string
+// like "#FF8800", "hsv(30, 100%, 100%)", "orange" or "2700K" (see ParseColor)
 type MetaColor struct {
 	R int `json:"R,omitempty"` // Red brightness level
 	G int `json:"G,omitempty"` // Green brightness level
 	B int `json:"B,omitempty"` // Blue brightness level
 	W int `json:"W,omitempty"` // White brightness level
+	K int `json:"K,omitempty"` // K is the color temperature (in Kelvin) for white colors.  RGBW strips use the white channel for these
 }
 
 type SolidMeta struct {
@@ -49,12 +108,23 @@ type SolidMeta struct {
 }
 
 type FadeMeta struct {
-	Color MetaColor `json:"color"` // Color indicates what color to fade to
+	Color      MetaColor   `json:"color"`                 // Color indicates what color to fade to
+	Easing     string      `json:"easing,omitempty"`      // Easing is the curve the fade follows.  Defaults to linear
+	FromColor  *MetaColor  `json:"from-color,omitempty"`  // FromColor is the color to start the fade from.  If not set, the fade starts from the pixels as they are
+	From       string      `json:"from,omitempty"`        // From can be 'current' to start from what the LEDs are actually showing (even from the previous timeline)
+	Sequence   []MetaColor `json:"sequence,omitempty"`    // Sequence is a repeating array of colors to fade each pixel to (instead of color)
+	Stops      []ColorStop `json:"stops,omitempty"`       // Stops are positioned colors (0 - 1) for a gradient to fade into (instead of color)
+	Palette    string      `json:"palette,omitempty"`     // Palette is the name of a stored palette to fade into as a gradient
+	ColorSpace string      `json:"color-space,omitempty"` // ColorSpace to blend the gradient in: rgb (default), hsv or oklab
 }
 
 type GradientMeta struct {
-	StartColor MetaColor `json:"start-color"` // StartColor indicates the first color in the gradient
-	EndColor   MetaColor `json:"end-color"`   // EndColor indicates the second color in the gradient
+	StartColor MetaColor   `json:"start-color"`           // StartColor indicates the first color in the gradient
+	EndColor   MetaColor   `json:"end-color"`             // EndColor indicates the second color in the gradient
+	Stops      []ColorStop `json:"stops,omitempty"`       // Stops are positioned colors (0 - 1).  When set, these are used instead of start/end color
+	Palette    string      `json:"palette,omitempty"`     // Palette is the name of a stored palette to use instead of start/end color
+	ColorSpace string      `json:"color-space,omitempty"` // ColorSpace to blend in: rgb, hsv or oklab.  Defaults to rgb
+	Speed      float64     `json:"speed,omitempty"`       // Speed to scroll the gradient in strip lengths per second.  Negative scrolls backwards.  0 is static
 }
 
 type SequenceMeta struct {
@@ -62,15 +132,166 @@ type SequenceMeta struct {
 }
 
 type ZipMeta struct {
-	Color MetaColor `json:"color"` // Color indicates what color to 'zip'
+	Color  MetaColor `json:"color"`            // Color indicates what color to 'zip'
+	Easing string    `json:"easing,omitempty"` // Easing is the curve the head of the zip follows.  Defaults to linear
 }
 
 type LightningMeta struct {
 	Bursts          int    `json:"bursts,omitempty"`           // Bursts indicates the number of bursts to fire in a single lightning effect
-	BurstType       string `json:"burst-type"`                 // BurstType can be 'fixed' or 'random'.  Defaults to random
+	BurstType       string `json:"burst-type"`                 // BurstType can be 'fixed' or 'random'.  Random strikes pick the number of bursts and each burst's spacing, length and brightness (up to the values set here).  Defaults to random
 	BurstSpacing    int    `json:"burst-spacing,omitempty"`    // BurstSpacing indicates how much time (in ms) should exist between bursts
-	BurstLength     int    `json:"burst-length,omitempty"`     // BurstLength indicates how long each flash should show
-	BurstBrightness int    `json:"burst-brightness,omitempty"` // BurstBrightness indicates how bright each flash is
+	BurstLength     int    `json:"burst-length,omitempty"`     // BurstLength indicates how long (in ms) each flash should show
+	BurstBrightness int    `json:"burst-brightness,omitempty"` // BurstBrightness indicates how bright (0 - 255) each flash is
+	StrikeSize      int    `json:"strike-size,omitempty"`      // StrikeSize indicates how many pixels each strike lights (a random section of the strip).  Defaults to the whole strip
+	StrikeFalloff   int    `json:"strike-falloff,omitempty"`   // StrikeFalloff indicates how many pixels the strike fades out over at each edge.  Defaults to a quarter of the strike size
+}
+
+type NoiseMeta struct {
+	Scale   float64     `json:"scale,omitempty"`   // Scale indicates how stretched the noise field is along the strip (or across the matrix).  Smaller is smoother
+	Speed   float64     `json:"speed,omitempty"`   // Speed indicates how quickly the noise field moves over time
+	Octaves int         `json:"octaves,omitempty"` // Octaves indicates how many layers of detail are added to the noise (1 - 8)
+	Colors  []MetaColor `json:"colors,omitempty"`  // Colors defines the palette the noise is mapped onto
+	Palette string      `json:"palette,omitempty"` // Palette indicates the name of a stored palette to use instead of Colors
+}
+
+type CandleMeta struct {
+	Temperature int     `json:"temperature,omitempty"` // Temperature indicates the base color temperature of the flames (in Kelvin).  Defaults to 1800
+	Brightness  int     `json:"brightness,omitempty"`  // Brightness indicates how bright an undisturbed flame is
+	Intensity   float64 `json:"intensity,omitempty"`   // Intensity indicates how deeply the flames flicker (0 - 1)
+	Wind        float64 `json:"wind,omitempty"`        // Wind indicates how often and how strongly gusts disturb the flames (0 - 1).  Defaults to no wind
+	GroupSize   int     `json:"group-size,omitempty"`  // GroupSize indicates how many pixels make up a single candle.  Defaults to 1
+}
+
+type BouncingBallsMeta struct {
+	Balls     int         `json:"balls,omitempty"`     // Balls indicates how many balls to drop.  Defaults to one for each color
+	Colors    []MetaColor `json:"colors,omitempty"`    // Colors indicates the ball colors.  If there are more balls than colors, the colors repeat
+	Gravity   float64     `json:"gravity,omitempty"`   // Gravity indicates how quickly the balls fall (in strip lengths per second squared).  Defaults to 9.81
+	Dampening float64     `json:"dampening,omitempty"` // Dampening indicates the portion of speed (0 - 1) a ball loses on each bounce
+	Relaunch  bool        `json:"relaunch,omitempty"`  // Relaunch indicates balls should be thrown back up once they come to rest
+}
+
+type StrobeMeta struct {
+	Color     MetaColor `json:"color"`                // Color indicates the color of each flash.  Defaults to full white
+	Frequency float64   `json:"frequency,omitempty"`  // Frequency indicates how many flashes per second (in Hz).  Defaults to 10
+	DutyCycle float64   `json:"duty-cycle,omitempty"` // DutyCycle indicates the portion (0 - 1) of each flash period the light is on.  Defaults to 0.2
+	Flashes   int       `json:"flashes,omitempty"`    // Flashes indicates how many flashes to show.  If not set, flashes until the step time is up (or until stopped)
+}
+
+type BeaconSegment struct {
+	Leds    string      `json:"leds,omitempty"`    // Leds indicates which leds the segment covers (for example "0-74").  Defaults to the step's leds (or the entire strip)
+	Colors  []MetaColor `json:"colors"`            // Colors indicates the colors the segment steps through, one per phase of the pattern.  An empty color is 'off'
+	Flashes int         `json:"flashes,omitempty"` // Flashes indicates how many quick flashes to show for each color.  Defaults to a steady color
+	Offset  int         `json:"offset,omitempty"`  // Offset indicates how far (in ms) into the pattern this segment starts
+	Rotate  bool        `json:"rotate,omitempty"`  // Rotate indicates the color should sweep around the segment like a rotating beacon
+}
+
+type BeaconMeta struct {
+	Preset   string          `json:"preset,omitempty"`   // Preset can be 'police', 'amber' or 'wig-wag'.  Ignored if segments are passed.  Defaults to police
+	Period   int             `json:"period,omitempty"`   // Period indicates the time (in ms) for one full pass through the pattern.  Defaults to 1000
+	Segments []BeaconSegment `json:"segments,omitempty"` // Segments defines a custom pattern
+}
+
+type ImageMeta struct {
+	Image   string  `json:"image"`             // Image is the name (or id) of an image in the image store
+	Rate    float64 `json:"rate,omitempty"`    // Rate indicates how many columns (or rows on a matrix) to show per second.  Defaults to 30
+	Loops   int     `json:"loops,omitempty"`   // Loops indicates how many times to play the image.  0 means keep looping for the step time
+	Reverse bool    `json:"reverse,omitempty"` // Reverse plays the image from the last column (or row) to the first
+}
+
+type GifMeta struct {
+	Image string `json:"image"`           // Image is the name (or id) of an animated gif in the image store
+	Loops int    `json:"loops,omitempty"` // Loops indicates how many times to play the animation.  0 means keep looping for the step time
+	Scale string `json:"scale,omitempty"` // Scale can be 'fit' (show the whole frame) or 'fill' (crop to fill the matrix).  Defaults to fit
+}
+
+type TextMeta struct {
+	Text      string      `json:"text"`                // Text is the message to show.  Can include {time}, {time12}, {date}, {day} and {year}
+	Font      string      `json:"font,omitempty"`      // Font can be '5x7' or '8x8'.  Defaults to 5x7
+	Color     MetaColor   `json:"color,omitempty"`     // Color indicates the color of the text.  Defaults to white
+	Colors    []MetaColor `json:"colors,omitempty"`    // Colors to spread across the message.  Used instead of color if set
+	Palette   string      `json:"palette,omitempty"`   // Palette is the name of a stored palette to spread across the message
+	Speed     float64     `json:"speed,omitempty"`     // Speed indicates how many pixels to scroll per second.  Defaults to 10
+	Direction string      `json:"direction,omitempty"` // Direction can be 'left', 'right', 'up' or 'down'.  Defaults to left
+	Repeat    int         `json:"repeat,omitempty"`    // Repeat indicates how many times to scroll the message.  0 means keep scrolling for the step time
+}
+
+type ClockMeta struct {
+	HourColor   MetaColor `json:"hour-color,omitempty"`   // HourColor indicates the color of the hour hand (or hour digits).  Defaults to red
+	MinuteColor MetaColor `json:"minute-color,omitempty"` // MinuteColor indicates the color of the minute hand (or minute digits).  Defaults to green
+	SecondColor MetaColor `json:"second-color,omitempty"` // SecondColor indicates the color of the second hand (or blinking colon).  Defaults to blue
+	MarkerColor MetaColor `json:"marker-color,omitempty"` // MarkerColor indicates the color of the hour markers on a ring.  Defaults to no markers
+	Format      string    `json:"format,omitempty"`       // Format can be '24h' or '12h'.  Defaults to 24h
+	Font        string    `json:"font,omitempty"`         // Font can be '5x7' or '8x8'.  Defaults to 5x7
+	Timezone    string    `json:"timezone,omitempty"`     // Timezone is the IANA timezone name (for example 'America/New_York').  Defaults to local time
+	Offset      int       `json:"offset,omitempty"`       // Offset is the led at 12 o'clock on a ring
+	Reverse     bool      `json:"reverse,omitempty"`      // Reverse runs the hands counter-clockwise on a ring
+}
+
+type CountdownMeta struct {
+	Target   string    `json:"target,omitempty"`   // Target is the time (RFC3339) to count down to
+	Duration int       `json:"duration,omitempty"` // Duration (in ms) to count down, if there's no target.  Defaults to the step time
+	Color    MetaColor `json:"color,omitempty"`    // Color indicates the color of the bar (or digits).  Defaults to white
+	Font     string    `json:"font,omitempty"`     // Font can be '5x7' or '8x8'.  Defaults to 5x7
+	Reverse  bool      `json:"reverse,omitempty"`  // Reverse anchors the bar at the end of the strip
+}
+
+type VUMeterMeta struct {
+	Colors      []MetaColor `json:"colors,omitempty"`      // Colors to spread along the meter.  Defaults to green, yellow, red
+	Palette     string      `json:"palette,omitempty"`     // Palette is the name of a stored palette to use instead of colors
+	PeakColor   MetaColor   `json:"peak-color,omitempty"`  // PeakColor indicates the color of the falling peak marker.  Defaults to no marker
+	Mirror      bool        `json:"mirror,omitempty"`      // Mirror grows the meter out from the middle of the strip
+	Sensitivity float64     `json:"sensitivity,omitempty"` // Sensitivity scales the audio level.  Defaults to 1
+}
+
+type SpectrumMeta struct {
+	Colors      []MetaColor `json:"colors,omitempty"`      // Colors to spread across the bands (low to high).  Defaults to a rainbow
+	Palette     string      `json:"palette,omitempty"`     // Palette is the name of a stored palette to use instead of colors
+	Sensitivity float64     `json:"sensitivity,omitempty"` // Sensitivity scales the band levels.  Defaults to 1
+}
+
+type BeatPulseMeta struct {
+	Colors  []MetaColor `json:"colors,omitempty"`  // Colors to step through, one per beat.  Defaults to a rainbow
+	Palette string      `json:"palette,omitempty"` // Palette is the name of a stored palette to use instead of colors
+	Decay   int         `json:"decay,omitempty"`   // Decay indicates the time (in ms) for each pulse to fade.  Defaults to 300
+}
+
+type FormulaMeta struct {
+	Formula string `json:"formula"` // Formula is evaluated for each pixel, every frame.  It sets h/s/v or r/g/b/w (all 0 - 1)
+}
+
+type ScriptMeta struct {
+	Script string         `json:"script"`           // Script is the name of the script in the script store
+	Params map[string]any `json:"params,omitempty"` // Params are passed to the script (as the 'params' table)
+}
+
+type SunriseMeta struct {
+	NightTemperature int `json:"night-temperature,omitempty"` // NightTemperature indicates the color temperature (in Kelvin) at the dark end.  Defaults to 1000 (a deep red)
+	DayTemperature   int `json:"day-temperature,omitempty"`   // DayTemperature indicates the color temperature (in Kelvin) at the bright end.  Defaults to 6500 (daylight)
+	Brightness       int `json:"brightness,omitempty"`        // Brightness indicates how bright (0 - 255) the bright end is.  Defaults to 255
+}
+
+type CircadianMeta struct {
+	Keyframes []CircadianKeyframe `json:"keyframes,omitempty"` // Keyframes set the color temperature and brightness through the day.  Defaults to a typical day
+}
+
+// CircadianKeyframe is the light at a time of day.  The light blends smoothly between keyframes
+type CircadianKeyframe struct {
+	Time        string `json:"time"`        // Time of day (local time), like '07:30' or '21:00'
+	Temperature int    `json:"temperature"` // Temperature indicates the color temperature (in Kelvin)
+	Brightness  int    `json:"brightness"`  // Brightness indicates how bright (0 - 255) the light is
+}
+
+// LoopMeta describes where a loop goes back to, and how many times.  Loops can be nested
+// (a loop inside another loop starts counting again each time the outer loop comes round)
+type LoopMeta struct {
+	Count int    `json:"count,omitempty"` // Count is how many times the steps in the loop play.  0 loops forever
+	Step  int    `json:"step,omitempty"`  // Step is the step number to go back to.  Defaults to the first step
+	Label string `json:"label,omitempty"` // Label is the name of the label step to go back to (instead of a step number)
+}
+
+// LabelMeta names a place in the timeline that loops can go back to
+type LabelMeta struct {
+	Name string `json:"name"` // Name is what loops call the label
 }
 
 type TriggerMeta struct {
@@ -0,0 +1,186 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rs/xid"
+	"github.com/rs/zerolog/log"
+	"time"
+)
+
+// ErrPaletteNotFound is returned when there isn't a palette with the requested id
+var ErrPaletteNotFound = errors.New("palette not found")
+
+func (a appDataService) AddPalette(ctx context.Context, source Palette) (Palette, error) {
+	//	Our return item
+	retval := Palette{
+		ID:      xid.New().String(), // Generate a new id
+		Name:    source.Name,
+		Created: time.Now(),
+		Stops:   source.Stops,
+	}
+
+	query := `insert into palette(id, name, created, stops)
+				values($1, $2, $3, $4);`
+
+	stmt, err := a.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return retval, err
+	}
+
+	//	Format stops as a json array:
+	jsonStops, _ := json.Marshal(source.Stops)
+
+	_, err = stmt.ExecContext(ctx, retval.ID, retval.Name, retval.Created.Format(time.DateTime), string(jsonStops))
+	if err != nil {
+		return retval, fmt.Errorf("problem adding palette: %v", err)
+	}
+
+	return retval, nil
+}
+
+func (a appDataService) GetPalette(ctx context.Context, id string) (Palette, error) {
+	//	Our return item
+	retval := Palette{
+		Stops: []ColorStop{},
+	}
+
+	query := `select id, name, created, stops from palette where id = $1;`
+
+	stmt, err := a.DB.PreparexContext(ctx, query)
+	if err != nil {
+		return retval, err
+	}
+
+	rows, err := stmt.QueryxContext(ctx, id)
+	if err != nil {
+		return retval, err
+	}
+
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Err(closeErr).Msg("unable to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		retval, err = scanPalette(rows.Scan)
+		if err != nil {
+			return retval, err
+		}
+	}
+
+	if retval.ID == "" {
+		return retval, fmt.Errorf("%w: %v", ErrPaletteNotFound, id)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+func (a appDataService) GetAllPalettes(ctx context.Context) ([]Palette, error) {
+	//	Our return item
+	retval := []Palette{}
+
+	query := `select id, name, created, stops from palette order by name;`
+
+	stmt, err := a.DB.PreparexContext(ctx, query)
+	if err != nil {
+		return retval, err
+	}
+
+	rows, err := stmt.QueryxContext(ctx)
+	if err != nil {
+		return retval, err
+	}
+
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Err(closeErr).Msg("unable to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		item, err := scanPalette(rows.Scan)
+		if err != nil {
+			return retval, err
+		}
+
+		retval = append(retval, item)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+func (a appDataService) UpdatePalette(ctx context.Context, id string, source Palette) (Palette, error) {
+
+	query := `update palette
+		set name = $1, stops = $2
+		where id = $3;`
+
+	stmt, err := a.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return Palette{}, err
+	}
+
+	//	Format stops as a json array:
+	jsonStops, _ := json.Marshal(source.Stops)
+
+	_, err = stmt.ExecContext(ctx, source.Name, string(jsonStops), id)
+	if err != nil {
+		return Palette{}, fmt.Errorf("problem executing query: %v", err)
+	}
+
+	return a.GetPalette(ctx, id)
+}
+
+func (a appDataService) DeletePalette(ctx context.Context, id string) error {
+
+	query := `delete from palette where id = $1;`
+
+	stmt, err := a.DB.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("problem preparing context: %v", err)
+	}
+
+	_, err = stmt.ExecContext(ctx, id)
+	if err != nil {
+		return fmt.Errorf("problem deleting palette: %v", err)
+	}
+
+	return nil
+}
+
+// scanPalette reads a single palette row using the passed scan function
+func scanPalette(scan func(dest ...any) error) (Palette, error) {
+	retval := Palette{
+		Stops: []ColorStop{},
+	}
+
+	createTime := ""
+	stops := []byte{}
+
+	if err := scan(&retval.ID, &retval.Name, &createTime, &stops); err != nil {
+		return retval, fmt.Errorf("problem reading into struct: %v", err)
+	}
+
+	//	Parse the time:
+	parsedDate, err := time.Parse(time.DateTime, createTime)
+	if err != nil {
+		return retval, fmt.Errorf("problem parsing create date: %v", err)
+	}
+	retval.Created = parsedDate
+
+	//	If we have data in stops ...
+	if stops != nil {
+		//	Unmarshal the JSON stop array
+		if err := json.Unmarshal(stops, &retval.Stops); err != nil {
+			return retval, fmt.Errorf("problem decoding stops for palette %v: %v", retval.ID, err)
+		}
+	}
+
+	return retval, nil
+}
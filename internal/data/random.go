@@ -0,0 +1,256 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/danesparza/fxpixel/internal/data/const/step"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The color temperatures (in Kelvin) a random white is picked from
+const (
+	randomWhiteMin = 1900
+	randomWhiteMax = 6500
+)
+
+// randomLimit is the biggest number (either way) a random range can reach, so whole
+// numbers fit in an int on every platform
+const randomLimit = math.MaxInt32
+
+// metaColorType lets us spot colors in meta info
+var metaColorType = reflect.TypeOf(MetaColor{})
+
+// ResolveRandom returns a copy of meta with its random values picked.  Values that are
+// picked once per play are kept in picked (by path), if it's passed.  The passed meta
+// is never changed
+func ResolveRandom(meta any, random map[string]RandomValue, rnd *rand.Rand, picked map[string]any) (any, error) {
+	if len(random) == 0 {
+		return meta, nil
+	}
+	if meta == nil {
+		return nil, fmt.Errorf("the step doesn't have any meta info to randomize")
+	}
+
+	v := reflect.New(reflect.TypeOf(meta)).Elem()
+	v.Set(reflect.ValueOf(meta))
+
+	//	Pick in path order, so the same seed always picks the same values
+	paths := []string{}
+	for path := range random {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		value := random[path]
+		perPlay := strings.EqualFold(value.Per, step.RANDOM_PER_PLAY) && picked != nil
+
+		err := setPath(v, strings.Split(path, "."), func(target reflect.Value) error {
+			if perPlay {
+				if p, found := picked[path]; found {
+					target.Set(reflect.ValueOf(p))
+					return nil
+				}
+			}
+
+			if err := value.pick(target, rnd); err != nil {
+				return err
+			}
+
+			if perPlay {
+				picked[path] = target.Interface()
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("problem with random value %q: %v", path, err)
+		}
+	}
+
+	return v.Interface(), nil
+}
+
+// CheckRandom makes sure the random values fit the meta info.  Every 'random-from'
+// choice is tried
+func CheckRandom(meta any, random map[string]RandomValue) error {
+	rnd := rand.New(rand.NewSource(1))
+
+	for path, value := range random {
+		choices := [][]json.RawMessage{value.RandomFrom}
+		if len(value.RandomFrom) > 1 {
+			choices = nil
+			for _, choice := range value.RandomFrom {
+				choices = append(choices, []json.RawMessage{choice})
+			}
+		}
+
+		for _, choice := range choices {
+			value.RandomFrom = choice
+			if _, err := ResolveRandom(meta, map[string]RandomValue{path: value}, rnd, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsRandomValue returns true if the passed (decoded JSON) object describes a random
+// value rather than a regular one
+func IsRandomValue(object map[string]any) bool {
+	_, hasMin := object["min"]
+	_, hasMax := object["max"]
+	_, hasRandom := object["random"]
+	_, hasRandomFrom := object["random-from"]
+	if !(hasMin && hasMax) && !hasRandom && !hasRandomFrom {
+		return false
+	}
+
+	for key := range object {
+		switch key {
+		case "min", "max", "random", "random-from", "per":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// pick sets target to a random value
+func (r RandomValue) pick(target reflect.Value, rnd *rand.Rand) error {
+	//	Fill in optional values (like *MetaColor)
+	if target.Kind() == reflect.Pointer {
+		p := reflect.New(target.Type().Elem())
+		if err := r.pick(p.Elem(), rnd); err != nil {
+			return err
+		}
+		target.Set(p)
+		return nil
+	}
+
+	switch {
+	case len(r.RandomFrom) > 0:
+		choice := reflect.New(target.Type())
+		if err := json.Unmarshal(r.RandomFrom[rnd.Intn(len(r.RandomFrom))], choice.Interface()); err != nil {
+			return err
+		}
+		target.Set(choice.Elem())
+
+	case r.Random != "":
+		if target.Type() != metaColorType {
+			return fmt.Errorf("'random' only picks colors")
+		}
+
+		var c MetaColor
+		switch strings.ToLower(r.Random) {
+		case step.RANDOM_HUE:
+			c = HSVColor(rnd.Float64()*360, 1, 1)
+		case step.RANDOM_COLOR:
+			c = MetaColor{R: rnd.Intn(256), G: rnd.Intn(256), B: rnd.Intn(256)}
+		case step.RANDOM_WHITE:
+			c = KelvinColor(randomWhiteMin + rnd.Intn(randomWhiteMax-randomWhiteMin+1))
+		default:
+			return fmt.Errorf("unknown random color %q (use hue, color or white)", r.Random)
+		}
+		target.Set(reflect.ValueOf(c))
+
+	case r.Min != nil && r.Max != nil:
+		lo, hi := *r.Min, *r.Max
+		if hi < lo {
+			lo, hi = hi, lo
+		}
+		if !(lo >= -randomLimit && hi <= randomLimit) {
+			return fmt.Errorf("'min' and 'max' have to be between %v and %v", -randomLimit, randomLimit)
+		}
+
+		switch target.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			l, h := int64(math.Ceil(lo)), int64(math.Floor(hi))
+			if h < l {
+				return fmt.Errorf("there aren't any whole numbers between %v and %v", lo, hi)
+			}
+			target.SetInt(l + rnd.Int63n(h-l+1))
+		case reflect.Float32, reflect.Float64:
+			target.SetFloat(lo + rnd.Float64()*(hi-lo))
+		case reflect.Interface:
+			target.Set(reflect.ValueOf(lo + rnd.Float64()*(hi-lo)))
+		default:
+			return fmt.Errorf("'min' and 'max' only pick numbers")
+		}
+
+	default:
+		return fmt.Errorf("a random value needs 'min' and 'max', 'random' or 'random-from'")
+	}
+
+	return nil
+}
+
+// setPath finds the value at path (JSON names and slice indexes) and calls set on it.
+// Slices, maps and pointers along the way are copied first
+func setPath(v reflect.Value, path []string, set func(reflect.Value) error) error {
+	if len(path) == 0 {
+		return set(v)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+			if name == path[0] && v.Field(i).CanSet() {
+				return setPath(v.Field(i), path[1:], set)
+			}
+		}
+		return fmt.Errorf("there isn't a %q value", path[0])
+
+	case reflect.Pointer:
+		c := reflect.New(v.Type().Elem())
+		if !v.IsNil() {
+			c.Elem().Set(v.Elem())
+		}
+		if err := setPath(c.Elem(), path, set); err != nil {
+			return err
+		}
+		v.Set(c)
+		return nil
+
+	case reflect.Slice:
+		i, err := strconv.Atoi(path[0])
+		if err != nil || i < 0 || i >= v.Len() {
+			return fmt.Errorf("there isn't an item %q", path[0])
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(c, v)
+		if err := setPath(c.Index(i), path[1:], set); err != nil {
+			return err
+		}
+		v.Set(c)
+		return nil
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len()+1)
+		for _, key := range v.MapKeys() {
+			c.SetMapIndex(key, v.MapIndex(key))
+		}
+		key := reflect.ValueOf(path[0]).Convert(v.Type().Key())
+		item := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			item.Set(existing)
+		}
+		if err := setPath(item, path[1:], set); err != nil {
+			return err
+		}
+		c.SetMapIndex(key, item)
+		v.Set(c)
+		return nil
+	}
+
+	return fmt.Errorf("can't look up %q in a %v", path[0], v.Type())
+}
@@ -0,0 +1,77 @@
+package data_test
+
+import (
+	"encoding/json"
+	"github.com/danesparza/fxpixel/internal/data"
+	"math/rand"
+	"testing"
+)
+
+func TestResolveRandom(t *testing.T) {
+	meta := data.FadeMeta{Sequence: []data.MetaColor{{R: 255}, {}}}
+	random := map[string]data.RandomValue{}
+	json.Unmarshal([]byte(`{
+		"color": {"random": "hue"},
+		"easing": {"random-from": ["ease-in", "linear"]},
+		"sequence.1": {"random-from": ["lime", "#0000FF"], "per": "play"}
+	}`), &random)
+
+	rnd := rand.New(rand.NewSource(1))
+	picked := map[string]any{}
+
+	var first data.MetaColor
+	for i := 0; i < 20; i++ {
+		resolved, err := data.ResolveRandom(meta, random, rnd, picked)
+		if err != nil {
+			t.Fatalf("ResolveRandom error = %v", err)
+		}
+
+		got := resolved.(data.FadeMeta)
+		if got.Easing != "ease-in" && got.Easing != "linear" {
+			t.Errorf("Easing = %v, want one of the choices", got.Easing)
+		}
+		if got.Color == (data.MetaColor{}) {
+			t.Errorf("Color wasn't picked")
+		}
+
+		//	Values picked per play stay the same
+		if i == 0 {
+			first = got.Sequence[1]
+		}
+		if got.Sequence[1] != first || got.Sequence[0] != (data.MetaColor{R: 255}) {
+			t.Errorf("Sequence = %+v, want the same second color each time", got.Sequence)
+		}
+	}
+
+	//	The original meta info isn't changed
+	if meta.Sequence[1] != (data.MetaColor{}) || meta.Color != (data.MetaColor{}) {
+		t.Errorf("ResolveRandom changed the passed meta info: %+v", meta)
+	}
+}
+
+func TestCheckRandom(t *testing.T) {
+	meta := data.LightningMeta{}
+
+	tests := []struct {
+		name    string
+		random  string
+		wantErr bool
+	}{
+		{name: "Number range", random: `{"bursts": {"min": 2, "max": 6}}`},
+		{name: "Unknown value", random: `{"flashes": {"min": 2, "max": 6}}`, wantErr: true},
+		{name: "Color for a number", random: `{"bursts": {"random": "hue"}}`, wantErr: true},
+		{name: "Bad choice", random: `{"bursts": {"random-from": [2, "lots"]}}`, wantErr: true},
+		{name: "Range too big", random: `{"bursts": {"min": -9e18, "max": 9e18}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			random := map[string]data.RandomValue{}
+			json.Unmarshal([]byte(tt.random), &random)
+
+			if err := data.CheckRandom(meta, random); (err != nil) != tt.wantErr {
+				t.Errorf("CheckRandom error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -23,6 +23,15 @@ type AppDataService interface {
 	GetAllTimelinesWithTag(ctx context.Context, tag string) ([]Timeline, error)
 	DeleteTimeline(ctx context.Context, id string) error
 	UpdateTags(ctx context.Context, id string, tags []string) error
+	AddPalette(ctx context.Context, source Palette) (Palette, error)
+	GetPalette(ctx context.Context, id string) (Palette, error)
+	GetAllPalettes(ctx context.Context) ([]Palette, error)
+	UpdatePalette(ctx context.Context, id string, source Palette) (Palette, error)
+	DeletePalette(ctx context.Context, id string) error
+	AddImage(ctx context.Context, source Image) (Image, error)
+	GetImage(ctx context.Context, id string) (Image, error)
+	GetAllImages(ctx context.Context) ([]Image, error)
+	DeleteImage(ctx context.Context, id string) error
 	GetSystemConfig(ctx context.Context) (SystemConfig, error)
 	SetSystemConfig(ctx context.Context, config SystemConfig) error
 }
@@ -0,0 +1,171 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	lua "github.com/yuin/gopher-lua"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// scriptExtension is the file extension for scripts in the script store
+const scriptExtension = ".lua"
+
+// ErrScriptNotFound is returned when there isn't a script with the requested name
+var ErrScriptNotFound = errors.New("script not found")
+
+// scriptNames keeps script names safe to use as file names
+var scriptNames = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// ScriptInfo describes a script in the script store
+type ScriptInfo struct {
+	Name     string
+	Size     int64
+	Modified time.Time
+}
+
+// scriptPath returns the path to the named script.  Names are case insensitive
+func scriptPath(dir, name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !scriptNames.MatchString(name) {
+		return "", fmt.Errorf("script names can only use letters, numbers, '-' and '_': %v", name)
+	}
+	return filepath.Join(dir, name+scriptExtension), nil
+}
+
+// CheckScript makes sure the script compiles
+func CheckScript(source string) error {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	defer L.Close()
+
+	_, err := L.LoadString(source)
+	return err
+}
+
+// GetAllScripts lists the scripts in the script store
+func GetAllScripts(dir string) ([]ScriptInfo, error) {
+	retval := []ScriptInfo{}
+
+	files, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return retval, nil
+	}
+	if err != nil {
+		return retval, err
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != scriptExtension {
+			continue
+		}
+
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+
+		retval = append(retval, ScriptInfo{
+			Name:     strings.TrimSuffix(file.Name(), scriptExtension),
+			Size:     info.Size(),
+			Modified: info.ModTime(),
+		})
+	}
+
+	sort.Slice(retval, func(i, j int) bool { return retval[i].Name < retval[j].Name })
+
+	return retval, nil
+}
+
+// GetScript gets a script (and its source) from the script store
+func GetScript(dir, name string) (ScriptInfo, string, error) {
+	path, err := scriptPath(dir, name)
+	if err != nil {
+		return ScriptInfo{}, "", err
+	}
+
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return ScriptInfo{}, "", fmt.Errorf("%w: %v", ErrScriptNotFound, name)
+	}
+	if err != nil {
+		return ScriptInfo{}, "", err
+	}
+
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return ScriptInfo{}, "", err
+	}
+
+	return ScriptInfo{
+		Name:     strings.TrimSuffix(filepath.Base(path), scriptExtension),
+		Size:     info.Size(),
+		Modified: info.ModTime(),
+	}, string(source), nil
+}
+
+// SaveScript checks the script compiles and saves it to the script store
+// (replacing any script with the same name)
+func SaveScript(dir, name, source string) (ScriptInfo, error) {
+	path, err := scriptPath(dir, name)
+	if err != nil {
+		return ScriptInfo{}, err
+	}
+
+	if err := CheckScript(source); err != nil {
+		return ScriptInfo{}, fmt.Errorf("problem compiling script: %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return ScriptInfo{}, err
+	}
+
+	if err := writeScript(dir, path, source); err != nil {
+		return ScriptInfo{}, err
+	}
+
+	info, _, err := GetScript(dir, name)
+	return info, err
+}
+
+// writeScript writes the source to a temp file next to the script and renames it into place,
+// so a running play (or a crash part way through) never sees a half written script
+func writeScript(dir, path, source string) error {
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(source); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// DeleteScript removes a script from the script store
+func DeleteScript(dir, name string) error {
+	path, err := scriptPath(dir, name)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("%w: %v", ErrScriptNotFound, name)
+	}
+	return err
+}
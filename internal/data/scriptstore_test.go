@@ -0,0 +1,75 @@
+package data_test
+
+import (
+	"errors"
+	"github.com/danesparza/fxpixel/internal/data"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveScript(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "scripts")
+
+	if _, err := data.SaveScript(dir, "chase", "return 1"); err != nil {
+		t.Fatalf("SaveScript error = %v", err)
+	}
+
+	//	Saving again replaces the script in place
+	info, err := data.SaveScript(dir, "chase", "return 2")
+	if err != nil {
+		t.Fatalf("SaveScript error = %v", err)
+	}
+	if info.Name != "chase" {
+		t.Errorf("SaveScript name = %v, want chase", info.Name)
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir error = %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("script store has %v files, want 1 (no temp files left behind)", len(files))
+	}
+
+	stat, err := os.Stat(filepath.Join(dir, files[0].Name()))
+	if err != nil {
+		t.Fatalf("Stat error = %v", err)
+	}
+	if got := stat.Mode().Perm(); got != 0644 {
+		t.Errorf("script mode = %v, want %v", got, os.FileMode(0644))
+	}
+
+	_, source, err := data.GetScript(dir, "chase")
+	if err != nil {
+		t.Fatalf("GetScript error = %v", err)
+	}
+	if source != "return 2" {
+		t.Errorf("GetScript source = %q, want %q", source, "return 2")
+	}
+}
+
+func TestScriptNotFound(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{name: "Get", call: func() error {
+			_, _, err := data.GetScript(dir, "missing")
+			return err
+		}},
+		{name: "Delete", call: func() error {
+			return data.DeleteScript(dir, "missing")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, data.ErrScriptNotFound) {
+				t.Errorf("error = %v, want ErrScriptNotFound", err)
+			}
+		})
+	}
+}
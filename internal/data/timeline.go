@@ -2,6 +2,7 @@ package data
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"github.com/danesparza/fxpixel/internal/data/const/effect"
@@ -21,6 +22,7 @@ func (a appDataService) AddTimeline(ctx context.Context, source Timeline) (Timel
 		GPIO:    source.GPIO,
 		Steps:   source.Steps,
 		Tags:    source.Tags,
+		Filters: source.Filters,
 	}
 
 	// Create a helper function for preparing failure results.
@@ -39,8 +41,8 @@ func (a appDataService) AddTimeline(ctx context.Context, source Timeline) (Timel
 	defer tx.Rollback()
 
 	//	Insert into the timeline table
-	query := `insert into timeline(id, enabled, created, name, gpio, tags) 
-				values($1, $2, $3, $4, $5, $6);`
+	query := `insert into timeline(id, enabled, created, name, gpio, tags, filters) 
+				values($1, $2, $3, $4, $5, $6, $7);`
 
 	stmt, err := tx.PrepareContext(ctx, query)
 	if err != nil {
@@ -50,15 +52,22 @@ func (a appDataService) AddTimeline(ctx context.Context, source Timeline) (Timel
 	//	Format tags as a json array:
 	jsonTags, _ := json.Marshal(source.Tags)
 
-	_, err = stmt.ExecContext(ctx, retval.ID, retval.Enabled, retval.Created.Format(time.DateTime), retval.Name, retval.GPIO, string(jsonTags))
+	//	Marshal the filters (if we have any) to JSON
+	filters := sql.NullString{}
+	if len(source.Filters) > 0 {
+		jsonFilters, _ := json.Marshal(source.Filters)
+		filters = sql.NullString{String: string(jsonFilters), Valid: true}
+	}
+
+	_, err = stmt.ExecContext(ctx, retval.ID, retval.Enabled, retval.Created.Format(time.DateTime), retval.Name, retval.GPIO, string(jsonTags), filters)
 	if err != nil {
 		return retval, fmt.Errorf("problem adding timeline: %v", err)
 	}
 
 	//	Insert each of the steps
 	for stepIndex, stepItem := range retval.Steps {
-		query := `insert into timeline_step(id, timeline_id, step_type_id, effect_type_id, led_range, step_time, step_meta, step_number) 
-				values($1, $2, $3, $4, $5, $6, $7, $8);`
+		query := `insert into timeline_step(id, timeline_id, step_type_id, effect_type_id, led_range, step_time, step_meta, step_number, step_transition, step_random, step_filters) 
+				values($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11);`
 
 		stmt, err := tx.PrepareContext(ctx, query)
 		if err != nil {
@@ -71,7 +80,28 @@ func (a appDataService) AddTimeline(ctx context.Context, source Timeline) (Timel
 		//	Marshal stepItem.MetaInfo to JSON
 		jsonString, _ := json.Marshal(stepItem.MetaInfo)
 
-		_, err = stmt.ExecContext(ctx, newId, retval.ID, stepItem.Type, stepItem.Effect, stepItem.Leds, stepItem.Time, string(jsonString), stepIndex+1)
+		//	Marshal the transition (if we have one) to JSON
+		transition := sql.NullString{}
+		if stepItem.Transition != nil {
+			jsonTransition, _ := json.Marshal(stepItem.Transition)
+			transition = sql.NullString{String: string(jsonTransition), Valid: true}
+		}
+
+		//	Marshal the random values (if we have any) to JSON
+		random := sql.NullString{}
+		if len(stepItem.Random) > 0 {
+			jsonRandom, _ := json.Marshal(stepItem.Random)
+			random = sql.NullString{String: string(jsonRandom), Valid: true}
+		}
+
+		//	Marshal the filters (if we have any) to JSON
+		stepFilters := sql.NullString{}
+		if len(stepItem.Filters) > 0 {
+			jsonFilters, _ := json.Marshal(stepItem.Filters)
+			stepFilters = sql.NullString{String: string(jsonFilters), Valid: true}
+		}
+
+		_, err = stmt.ExecContext(ctx, newId, retval.ID, stepItem.Type, stepItem.Effect, stepItem.Leds, stepItem.Time, string(jsonString), stepIndex+1, transition, random, stepFilters)
 		if err != nil {
 			return retval, fmt.Errorf("problem adding step: %v", err)
 		}
@@ -96,9 +126,9 @@ func (a appDataService) GetTimeline(ctx context.Context, id string) (Timeline, e
 	}
 
 	query := `select
-		tl.id, tl.enabled, tl.created, tl.name, tl.gpio, tl.tags,
+		tl.id, tl.enabled, tl.created, tl.name, tl.gpio, tl.tags, tl.filters,
 		ts.id, ts.step_type_id, ts.effect_type_id, ts.led_range,
-		ts.step_time, ts.step_meta, ts.step_number
+		ts.step_time, ts.step_meta, ts.step_number, ts.step_transition, ts.step_random, ts.step_filters
 	from
 		timeline tl
 		join timeline_step ts
@@ -127,15 +157,44 @@ func (a appDataService) GetTimeline(ctx context.Context, id string) (Timeline, e
 	for rows.Next() {
 		tlStep := TimelineStep{}
 		tags := []byte{}
+		transition := sql.NullString{}
+		random := sql.NullString{}
+		filters := sql.NullString{}
+		stepFilters := sql.NullString{}
 
 		createTime := ""
 
-		if err := rows.Scan(&retval.ID, &retval.Enabled, &createTime, &retval.Name, &retval.GPIO, &tags,
+		if err := rows.Scan(&retval.ID, &retval.Enabled, &createTime, &retval.Name, &retval.GPIO, &tags, &filters,
 			&tlStep.ID, &tlStep.Type, &tlStep.Effect, &tlStep.Leds,
-			&tlStep.Time, &tlStep.MetaInfo, &tlStep.Number); err != nil {
+			&tlStep.Time, &tlStep.MetaInfo, &tlStep.Number, &transition, &random, &stepFilters); err != nil {
 			return retval, fmt.Errorf("problem reading into struct: %v", err)
 		}
 
+		//	If the step has a transition, decode it
+		if transition.Valid {
+			tlStep.Transition = &StepTransition{}
+			if err := json.Unmarshal([]byte(transition.String), tlStep.Transition); err != nil {
+				log.Err(err).Str("stepid", tlStep.ID).Msg("problem decoding step transition")
+				tlStep.Transition = nil
+			}
+		}
+
+		//	If the step has random values, decode them
+		if random.Valid {
+			if err := json.Unmarshal([]byte(random.String), &tlStep.Random); err != nil {
+				log.Err(err).Str("stepid", tlStep.ID).Msg("problem decoding step random values")
+				tlStep.Random = nil
+			}
+		}
+
+		//	If the step has filters, decode them
+		if stepFilters.Valid {
+			if err := json.Unmarshal([]byte(stepFilters.String), &tlStep.Filters); err != nil {
+				log.Err(err).Str("stepid", tlStep.ID).Msg("problem decoding step filters")
+				tlStep.Filters = nil
+			}
+		}
+
 		//	Parse the time:
 		parsedDate, err := time.Parse(time.DateTime, createTime)
 		if err != nil {
@@ -191,10 +250,85 @@ func (a appDataService) GetTimeline(ctx context.Context, id string) (Timeline, e
 					em := LightningMeta{}
 					json.Unmarshal([]byte(jsonString), &em)
 					tlStep.MetaInfo = em
+				case effect.Plasma, effect.Noise:
+					em := NoiseMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Candle:
+					em := CandleMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.BouncingBalls:
+					em := BouncingBallsMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Strobe:
+					em := StrobeMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Beacon:
+					em := BeaconMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Image:
+					em := ImageMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Gif:
+					em := GifMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Text:
+					em := TextMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Clock:
+					em := ClockMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Countdown:
+					em := CountdownMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.VUMeter:
+					em := VUMeterMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Spectrum:
+					em := SpectrumMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.BeatPulse:
+					em := BeatPulseMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Formula:
+					em := FormulaMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Script:
+					em := ScriptMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Sunrise, effect.Sunset:
+					em := SunriseMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Circadian:
+					em := CircadianMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
 				}
 			case step.Sleep:
 			case step.RandomSleep:
 			case step.Loop:
+				em := LoopMeta{}
+				json.Unmarshal([]byte(jsonString), &em)
+				tlStep.MetaInfo = em
+			case step.Label:
+				em := LabelMeta{}
+				json.Unmarshal([]byte(jsonString), &em)
+				tlStep.MetaInfo = em
 			case step.Trigger:
 				em := TriggerMeta{}
 				json.Unmarshal([]byte(jsonString), &em)
@@ -213,6 +347,14 @@ func (a appDataService) GetTimeline(ctx context.Context, id string) (Timeline, e
 				return retval, fmt.Errorf("problem decoding tags for timeline %v: %v", retval.ID, err)
 			}
 		}
+
+		//	If the timeline has filters, decode them
+		if filters.Valid {
+			if err := json.Unmarshal([]byte(filters.String), &retval.Filters); err != nil {
+				log.Err(err).Str("timelineid", retval.ID).Msg("problem decoding timeline filters")
+				retval.Filters = nil
+			}
+		}
 	}
 
 	//	Return our data:
@@ -227,9 +369,9 @@ func (a appDataService) GetAllTimelines(ctx context.Context) ([]Timeline, error)
 	timelines := map[string]Timeline{}
 
 	query := `select
-		tl.id, tl.enabled, tl.created, tl.name, tl.gpio, tl.tags,
+		tl.id, tl.enabled, tl.created, tl.name, tl.gpio, tl.tags, tl.filters,
 		ts.id, ts.step_type_id, ts.effect_type_id, ts.led_range,
-		ts.step_time, ts.step_meta, ts.step_number
+		ts.step_time, ts.step_meta, ts.step_number, ts.step_transition, ts.step_random, ts.step_filters
 	from
 		timeline tl
 		join timeline_step ts
@@ -260,15 +402,44 @@ func (a appDataService) GetAllTimelines(ctx context.Context) ([]Timeline, error)
 		}
 		tlStep := TimelineStep{}
 		tags := []byte{}
+		transition := sql.NullString{}
+		random := sql.NullString{}
+		filters := sql.NullString{}
+		stepFilters := sql.NullString{}
 
 		createTime := ""
 
-		if err := rows.Scan(&item.ID, &item.Enabled, &createTime, &item.Name, &item.GPIO, &tags,
+		if err := rows.Scan(&item.ID, &item.Enabled, &createTime, &item.Name, &item.GPIO, &tags, &filters,
 			&tlStep.ID, &tlStep.Type, &tlStep.Effect, &tlStep.Leds,
-			&tlStep.Time, &tlStep.MetaInfo, &tlStep.Number); err != nil {
+			&tlStep.Time, &tlStep.MetaInfo, &tlStep.Number, &transition, &random, &stepFilters); err != nil {
 			return retval, fmt.Errorf("problem reading into struct: %v", err)
 		}
 
+		//	If the step has a transition, decode it
+		if transition.Valid {
+			tlStep.Transition = &StepTransition{}
+			if err := json.Unmarshal([]byte(transition.String), tlStep.Transition); err != nil {
+				log.Err(err).Str("stepid", tlStep.ID).Msg("problem decoding step transition")
+				tlStep.Transition = nil
+			}
+		}
+
+		//	If the step has random values, decode them
+		if random.Valid {
+			if err := json.Unmarshal([]byte(random.String), &tlStep.Random); err != nil {
+				log.Err(err).Str("stepid", tlStep.ID).Msg("problem decoding step random values")
+				tlStep.Random = nil
+			}
+		}
+
+		//	If the step has filters, decode them
+		if stepFilters.Valid {
+			if err := json.Unmarshal([]byte(stepFilters.String), &tlStep.Filters); err != nil {
+				log.Err(err).Str("stepid", tlStep.ID).Msg("problem decoding step filters")
+				tlStep.Filters = nil
+			}
+		}
+
 		//	Parse the time:
 		parsedDate, err := time.Parse(time.DateTime, createTime)
 		if err != nil {
@@ -284,6 +455,14 @@ func (a appDataService) GetAllTimelines(ctx context.Context) ([]Timeline, error)
 			}
 		}
 
+		//	If the timeline has filters, decode them
+		if filters.Valid {
+			if err := json.Unmarshal([]byte(filters.String), &item.Filters); err != nil {
+				log.Err(err).Str("timelineid", item.ID).Msg("problem decoding timeline filters")
+				item.Filters = nil
+			}
+		}
+
 		//	If the tracked timeline doesn't exist yet, add it:
 		_, found := timelines[item.ID]
 		if !found {
@@ -338,10 +517,85 @@ func (a appDataService) GetAllTimelines(ctx context.Context) ([]Timeline, error)
 					em := LightningMeta{}
 					json.Unmarshal([]byte(jsonString), &em)
 					tlStep.MetaInfo = em
+				case effect.Plasma, effect.Noise:
+					em := NoiseMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Candle:
+					em := CandleMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.BouncingBalls:
+					em := BouncingBallsMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Strobe:
+					em := StrobeMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Beacon:
+					em := BeaconMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Image:
+					em := ImageMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Gif:
+					em := GifMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Text:
+					em := TextMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Clock:
+					em := ClockMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Countdown:
+					em := CountdownMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.VUMeter:
+					em := VUMeterMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Spectrum:
+					em := SpectrumMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.BeatPulse:
+					em := BeatPulseMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Formula:
+					em := FormulaMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Script:
+					em := ScriptMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Sunrise, effect.Sunset:
+					em := SunriseMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Circadian:
+					em := CircadianMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
 				}
 			case step.Sleep:
 			case step.RandomSleep:
 			case step.Loop:
+				em := LoopMeta{}
+				json.Unmarshal([]byte(jsonString), &em)
+				tlStep.MetaInfo = em
+			case step.Label:
+				em := LabelMeta{}
+				json.Unmarshal([]byte(jsonString), &em)
+				tlStep.MetaInfo = em
 			case step.Trigger:
 				em := TriggerMeta{}
 				json.Unmarshal([]byte(jsonString), &em)
@@ -378,9 +632,9 @@ func (a appDataService) GetAllTimelinesWithTag(ctx context.Context, tag string)
 	timelines := map[string]Timeline{}
 
 	query := `select
-		tl.id, tl.enabled, tl.created, tl.name, tl.gpio, tl.tags,
+		tl.id, tl.enabled, tl.created, tl.name, tl.gpio, tl.tags, tl.filters,
 		ts.id, ts.step_type_id, ts.effect_type_id, ts.led_range,
-		ts.step_time, ts.step_meta, ts.step_number
+		ts.step_time, ts.step_meta, ts.step_number, ts.step_transition, ts.step_random, ts.step_filters
 	from
 		timeline tl, json_each(tl.tags)
 		join timeline_step ts
@@ -413,15 +667,44 @@ func (a appDataService) GetAllTimelinesWithTag(ctx context.Context, tag string)
 		}
 		tlStep := TimelineStep{}
 		tags := []byte{}
+		transition := sql.NullString{}
+		random := sql.NullString{}
+		filters := sql.NullString{}
+		stepFilters := sql.NullString{}
 
 		createTime := ""
 
-		if err := rows.Scan(&item.ID, &item.Enabled, &createTime, &item.Name, &item.GPIO, &tags,
+		if err := rows.Scan(&item.ID, &item.Enabled, &createTime, &item.Name, &item.GPIO, &tags, &filters,
 			&tlStep.ID, &tlStep.Type, &tlStep.Effect, &tlStep.Leds,
-			&tlStep.Time, &tlStep.MetaInfo, &tlStep.Number); err != nil {
+			&tlStep.Time, &tlStep.MetaInfo, &tlStep.Number, &transition, &random, &stepFilters); err != nil {
 			return retval, fmt.Errorf("problem reading into struct: %v", err)
 		}
 
+		//	If the step has a transition, decode it
+		if transition.Valid {
+			tlStep.Transition = &StepTransition{}
+			if err := json.Unmarshal([]byte(transition.String), tlStep.Transition); err != nil {
+				log.Err(err).Str("stepid", tlStep.ID).Msg("problem decoding step transition")
+				tlStep.Transition = nil
+			}
+		}
+
+		//	If the step has random values, decode them
+		if random.Valid {
+			if err := json.Unmarshal([]byte(random.String), &tlStep.Random); err != nil {
+				log.Err(err).Str("stepid", tlStep.ID).Msg("problem decoding step random values")
+				tlStep.Random = nil
+			}
+		}
+
+		//	If the step has filters, decode them
+		if stepFilters.Valid {
+			if err := json.Unmarshal([]byte(stepFilters.String), &tlStep.Filters); err != nil {
+				log.Err(err).Str("stepid", tlStep.ID).Msg("problem decoding step filters")
+				tlStep.Filters = nil
+			}
+		}
+
 		//	Parse the time:
 		parsedDate, err := time.Parse(time.DateTime, createTime)
 		if err != nil {
@@ -437,6 +720,14 @@ func (a appDataService) GetAllTimelinesWithTag(ctx context.Context, tag string)
 			}
 		}
 
+		//	If the timeline has filters, decode them
+		if filters.Valid {
+			if err := json.Unmarshal([]byte(filters.String), &item.Filters); err != nil {
+				log.Err(err).Str("timelineid", item.ID).Msg("problem decoding timeline filters")
+				item.Filters = nil
+			}
+		}
+
 		//	If the tracked timeline doesn't exist yet, add it:
 		_, found := timelines[item.ID]
 		if !found {
@@ -491,10 +782,85 @@ func (a appDataService) GetAllTimelinesWithTag(ctx context.Context, tag string)
 					em := LightningMeta{}
 					json.Unmarshal([]byte(jsonString), &em)
 					tlStep.MetaInfo = em
+				case effect.Plasma, effect.Noise:
+					em := NoiseMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Candle:
+					em := CandleMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.BouncingBalls:
+					em := BouncingBallsMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Strobe:
+					em := StrobeMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Beacon:
+					em := BeaconMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Image:
+					em := ImageMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Gif:
+					em := GifMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Text:
+					em := TextMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Clock:
+					em := ClockMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Countdown:
+					em := CountdownMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.VUMeter:
+					em := VUMeterMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Spectrum:
+					em := SpectrumMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.BeatPulse:
+					em := BeatPulseMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Formula:
+					em := FormulaMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Script:
+					em := ScriptMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Sunrise, effect.Sunset:
+					em := SunriseMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
+				case effect.Circadian:
+					em := CircadianMeta{}
+					json.Unmarshal([]byte(jsonString), &em)
+					tlStep.MetaInfo = em
 				}
 			case step.Sleep:
 			case step.RandomSleep:
 			case step.Loop:
+				em := LoopMeta{}
+				json.Unmarshal([]byte(jsonString), &em)
+				tlStep.MetaInfo = em
+			case step.Label:
+				em := LabelMeta{}
+				json.Unmarshal([]byte(jsonString), &em)
+				tlStep.MetaInfo = em
 			default:
 			}
 
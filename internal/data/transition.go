@@ -0,0 +1,29 @@
+package data
+
+import (
+	"fmt"
+	"github.com/danesparza/fxpixel/internal/data/const/step"
+	"github.com/danesparza/fxpixel/internal/ease"
+	"strings"
+)
+
+// CheckTransition makes sure the transition type and easing curve are ones we know
+func CheckTransition(transition StepTransition) error {
+	switch strings.ToLower(transition.Type) {
+	case "", step.TRANSITION_CROSSFADE, step.TRANSITION_WIPE, step.TRANSITION_DISSOLVE:
+	default:
+		return fmt.Errorf("unknown transition %q", transition.Type)
+	}
+
+	if transition.Duration < 0 {
+		return fmt.Errorf("the transition duration can't be negative")
+	}
+
+	if strings.TrimSpace(transition.Easing) != "" {
+		if _, err := ease.Parse(transition.Easing); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package data_test
+
+import (
+	"github.com/danesparza/fxpixel/internal/data"
+	"testing"
+)
+
+func TestCheckTransition(t *testing.T) {
+	tests := []struct {
+		name       string
+		transition data.StepTransition
+		wantErr    bool
+	}{
+		{name: "No transition", transition: data.StepTransition{}},
+		{name: "Crossfade", transition: data.StepTransition{Type: "crossfade", Duration: 500}},
+		{name: "Any case", transition: data.StepTransition{Type: "Wipe", Duration: 500}},
+		{name: "Dissolve with easing", transition: data.StepTransition{Type: "dissolve", Duration: 500, Easing: "ease-in-out-cubic"}},
+		{name: "Custom easing", transition: data.StepTransition{Type: "crossfade", Easing: "cubic-bezier(0.25, 0.1, 0.25, 1)"}},
+		{name: "Unknown type", transition: data.StepTransition{Type: "spin"}, wantErr: true},
+		{name: "Negative duration", transition: data.StepTransition{Type: "crossfade", Duration: -1}, wantErr: true},
+		{name: "Unknown easing", transition: data.StepTransition{Type: "wipe", Easing: "wobbly"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := data.CheckTransition(tt.transition); (err != nil) != tt.wantErr {
+				t.Errorf("CheckTransition(%+v) error = %v, wantErr %v", tt.transition, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Package ease has the easing curves used by fades, zips and transitions.  Curves are
+// named like CSS (ease-in-out, ease-out-cubic, cubic-bezier(x1, y1, x2, y2) ...)
+package ease
+
+import (
+	"fmt"
+	"github.com/danesparza/fxpixel/internal/data/const/effect"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Func maps linear progress (0 - 1) onto an easing curve.  Some curves (like
+// elastic) overshoot, so the result can be a little outside of 0 - 1
+type Func func(t float64) float64
+
+// easeIn curves, by family.  The ease-out and ease-in-out versions are derived from these
+var easeInCurves = map[string]Func{
+	"quad":  func(t float64) float64 { return t * t },
+	"cubic": func(t float64) float64 { return t * t * t },
+	"sine":  func(t float64) float64 { return 1 - math.Cos(t*math.Pi/2) },
+	"expo": func(t float64) float64 {
+		if t <= 0 {
+			return 0
+		}
+		return math.Pow(2, 10*t-10)
+	},
+	"bounce": func(t float64) float64 { return 1 - bounceOut(1-t) },
+	"elastic": func(t float64) float64 {
+		if t <= 0 || t >= 1 {
+			return t
+		}
+		return -math.Pow(2, 10*t-10) * math.Sin((t*10-10.75)*(2*math.Pi)/3)
+	},
+}
+
+// bounceOut is the classic 'ball dropping on the floor' curve
+func bounceOut(t float64) float64 {
+	const n, d = 7.5625, 2.75
+
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
+
+// Parse parses an easing curve name.  A blank name is linear
+func Parse(name string) (Func, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	//	Shorthand curves
+	switch name {
+	case effect.EASE_LINEAR, "":
+		return func(t float64) float64 { return t }, nil
+	case effect.EASE_IN:
+		name = effect.EASE_IN_QUAD
+	case effect.EASE_OUT:
+		name = effect.EASE_OUT_QUAD
+	case effect.EASE_IN_OUT:
+		name = effect.EASE_IN_OUT_SINE
+	}
+
+	if strings.HasPrefix(name, effect.EASE_CUBIC_BEZIER) {
+		return parseCubicBezier(name)
+	}
+
+	//	ease-in-out-x, ease-in-x or ease-out-x
+	for _, mode := range []string{"ease-in-out-", "ease-in-", "ease-out-"} {
+		family, found := strings.CutPrefix(name, mode)
+		if !found {
+			continue
+		}
+
+		in, ok := easeInCurves[family]
+		if !ok {
+			break
+		}
+
+		switch mode {
+		case "ease-in-":
+			return in, nil
+		case "ease-out-":
+			return func(t float64) float64 { return 1 - in(1-t) }, nil
+		default:
+			return func(t float64) float64 {
+				if t < 0.5 {
+					return in(2*t) / 2
+				}
+				return 1 - in(2-2*t)/2
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown easing curve: %v", name)
+}
+
+// parseCubicBezier parses a custom curve like cubic-bezier(0.25, 0.1, 0.25, 1)
+func parseCubicBezier(name string) (Func, error) {
+	args, found := strings.CutPrefix(name, effect.EASE_CUBIC_BEZIER)
+	args = strings.TrimSpace(args)
+	if !found || !strings.HasPrefix(args, "(") || !strings.HasSuffix(args, ")") {
+		return nil, fmt.Errorf("cubic-bezier should look like cubic-bezier(x1, y1, x2, y2): %v", name)
+	}
+
+	parts := strings.Split(args[1:len(args)-1], ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("cubic-bezier needs 4 numbers: %v", name)
+	}
+
+	p := [4]float64{}
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, fmt.Errorf("problem parsing cubic-bezier: %v", err)
+		}
+		p[i] = f
+	}
+
+	//	The x values have to stay in range, so the curve is a function of time
+	if p[0] < 0 || p[0] > 1 || p[2] < 0 || p[2] > 1 {
+		return nil, fmt.Errorf("cubic-bezier x values must be between 0 and 1: %v", name)
+	}
+
+	return cubicBezier(p[0], p[1], p[2], p[3]), nil
+}
+
+// cubicBezier creates a curve from (0,0) to (1,1) with the passed control points
+func cubicBezier(x1, y1, x2, y2 float64) Func {
+	//	Polynomial coefficients for each axis
+	cx := 3 * x1
+	bx := 3*(x2-x1) - cx
+	ax := 1 - cx - bx
+	cy := 3 * y1
+	by := 3*(y2-y1) - cy
+	ay := 1 - cy - by
+
+	sample := func(a, b, c, s float64) float64 { return ((a*s+b)*s + c) * s }
+
+	return func(t float64) float64 {
+		//	Find the curve parameter for the time (x) with Newton's method ...
+		s := t
+		for i := 0; i < 8; i++ {
+			x := sample(ax, bx, cx, s) - t
+			if math.Abs(x) < 1e-6 {
+				return sample(ay, by, cy, s)
+			}
+			dx := (3*ax*s+2*bx)*s + cx
+			if math.Abs(dx) < 1e-6 {
+				break
+			}
+			s -= x / dx
+		}
+
+		//	... falling back to bisection if that doesn't converge
+		lo, hi := 0.0, 1.0
+		s = t
+		for i := 0; i < 30; i++ {
+			x := sample(ax, bx, cx, s)
+			if math.Abs(x-t) < 1e-6 {
+				break
+			}
+			if x < t {
+				lo = s
+			} else {
+				hi = s
+			}
+			s = (lo + hi) / 2
+		}
+
+		return sample(ay, by, cy, s)
+	}
+}
@@ -0,0 +1,80 @@
+package ease_test
+
+import (
+	"github.com/danesparza/fxpixel/internal/ease"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		curve   string
+		t       float64
+		want    float64
+		wantErr bool
+	}{
+		{name: "Blank is linear", curve: "", t: 0.3, want: 0.3},
+		{name: "Linear", curve: "linear", t: 0.7, want: 0.7},
+		{name: "Any case", curve: " Ease-In-Quad ", t: 0.5, want: 0.25},
+		{name: "Ease in is quad", curve: "ease-in", t: 0.5, want: 0.25},
+		{name: "Ease out is quad", curve: "ease-out", t: 0.5, want: 0.75},
+		{name: "Ease in out is sine", curve: "ease-in-out", t: 0.25, want: (1 - math.Cos(math.Pi/4)) / 2},
+		{name: "Ease in cubic", curve: "ease-in-cubic", t: 0.5, want: 0.125},
+		{name: "Ease out cubic", curve: "ease-out-cubic", t: 0.5, want: 0.875},
+		{name: "Ease in out cubic", curve: "ease-in-out-cubic", t: 0.25, want: 0.0625},
+		{name: "Ease in out midpoint", curve: "ease-in-out-expo", t: 0.5, want: 0.5},
+		{name: "Ease in expo start", curve: "ease-in-expo", t: 0, want: 0},
+		{name: "Ease out bounce", curve: "ease-out-bounce", t: 1 / 2.75, want: 1},
+		{name: "Ease in elastic end", curve: "ease-in-elastic", t: 1, want: 1},
+		{name: "Cubic bezier linear", curve: "cubic-bezier(0, 0, 1, 1)", t: 0.3, want: 0.3},
+		{name: "Cubic bezier symmetric midpoint", curve: "cubic-bezier(0.42, 0, 0.58, 1)", t: 0.5, want: 0.5},
+		{name: "Cubic bezier overshoot", curve: "cubic-bezier(0.5, 2, 0.5, 2)", t: 0.5, want: 1.625},
+		{name: "Unknown curve", curve: "ease-sideways", wantErr: true},
+		{name: "Unknown family", curve: "ease-in-wobble", wantErr: true},
+		{name: "Cubic bezier missing bracket", curve: "cubic-bezier(0, 0, 1, 1", wantErr: true},
+		{name: "Cubic bezier too few numbers", curve: "cubic-bezier(0, 0, 1)", wantErr: true},
+		{name: "Cubic bezier not a number", curve: "cubic-bezier(0, a, 1, 1)", wantErr: true},
+		{name: "Cubic bezier x out of range", curve: "cubic-bezier(1.5, 0, 1, 1)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ease.Parse(tt.curve)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.curve, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := f(tt.t); math.Abs(got-tt.want) > 1e-4 {
+				t.Errorf("%v(%v) = %v, want %v", tt.curve, tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurveEnds(t *testing.T) {
+	//	Every curve starts at 0 and finishes at 1
+	families := []string{"quad", "cubic", "sine", "expo", "bounce", "elastic"}
+	modes := []string{"ease-in-", "ease-out-", "ease-in-out-"}
+
+	for _, family := range families {
+		for _, mode := range modes {
+			name := mode + family
+			t.Run(name, func(t *testing.T) {
+				f, err := ease.Parse(name)
+				if err != nil {
+					t.Fatalf("Parse(%q) error = %v", name, err)
+				}
+				if got := f(0); math.Abs(got) > 1e-3 {
+					t.Errorf("%v(0) = %v, want 0", name, got)
+				}
+				if got := f(1); math.Abs(got-1) > 1e-3 {
+					t.Errorf("%v(1) = %v, want 1", name, got)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,488 @@
+// Package formula is a small, sandboxed expression language for per-pixel effects.
+//
+// A formula is a list of assignments, separated by new lines or semicolons:
+//
+//	h = t * 0.1 + x
+//	v = wave(x * 3 - t) ^ 2
+//
+// Everything is a number.  There are the usual math operators (including ^ for power),
+// comparisons and && / || (which give 1 or 0), the ternary ?: and a set of built in
+// functions.  There are no loops, so every formula runs in a bounded amount of time.
+package formula
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Limits that keep a formula from using up the stack (or the cpu) while it compiles
+const (
+	maxLength = 4096 // Longest formula source, in bytes
+	maxDepth  = 64   // Deepest nesting of brackets, ternaries and unary operators
+)
+
+// expr evaluates to a number, given the current variable values
+type expr func(vars []float64) float64
+
+// Program is a compiled formula.  It isn't safe to run a program from more than one goroutine at once
+type Program struct {
+	slots      map[string]int
+	assigned   map[string]bool
+	statements []func(vars []float64)
+	rnd        *rand.Rand
+}
+
+// Compile compiles the formula.  Names are the variables the caller provides (inputs)
+// or reads back (outputs).  Any other variable has to be assigned before it's used
+func Compile(src string, names ...string) (*Program, error) {
+	if len(src) > maxLength {
+		return nil, fmt.Errorf("formula: the formula is longer than %v characters", maxLength)
+	}
+
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, fmt.Errorf("formula: %v", err)
+	}
+
+	p := parser{
+		tokens: tokens,
+		program: &Program{
+			slots:    map[string]int{},
+			assigned: map[string]bool{},
+			rnd:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		},
+		defined: map[string]bool{},
+	}
+
+	for _, name := range names {
+		p.program.slot(name)
+		p.defined[name] = true
+	}
+
+	if err := p.parseProgram(); err != nil {
+		return nil, fmt.Errorf("formula: %v", err)
+	}
+
+	return p.program, nil
+}
+
+// slot finds (or makes) the variable slot for the name
+func (p *Program) slot(name string) int {
+	if i, found := p.slots[name]; found {
+		return i
+	}
+	p.slots[name] = len(p.slots)
+	return p.slots[name]
+}
+
+// Vars creates the storage for the program's variables
+func (p *Program) Vars() []float64 {
+	return make([]float64, len(p.slots))
+}
+
+// Slot returns the index of the named variable in the storage from Vars
+func (p *Program) Slot(name string) (int, bool) {
+	i, found := p.slots[name]
+	return i, found
+}
+
+// Assigns returns true if the formula assigns to the named variable
+func (p *Program) Assigns(name string) bool {
+	return p.assigned[name]
+}
+
+// SetRand sets where random() gets its numbers from, so a seeded generator repeats
+// the same values
+func (p *Program) SetRand(rnd *rand.Rand) {
+	p.rnd = rnd
+}
+
+// Run runs the formula against the passed variables
+func (p *Program) Run(vars []float64) {
+	for _, s := range p.statements {
+		s(vars)
+	}
+}
+
+type parser struct {
+	tokens  []token
+	pos     int
+	program *Program
+	defined map[string]bool
+	depth   int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token if it's the passed operator
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		return p.unexpected(fmt.Sprintf("'%v'", op))
+	}
+	return nil
+}
+
+// enter tracks how deeply the parser has recursed.  Call the returned func on the way out
+func (p *parser) enter() (func(), error) {
+	if p.depth >= maxDepth {
+		return nil, fmt.Errorf("the formula is nested more than %v deep at %v", maxDepth, p.peek().pos)
+	}
+	p.depth++
+	return func() { p.depth-- }, nil
+}
+
+func (p *parser) unexpected(want string) error {
+	t := p.peek()
+	switch t.kind {
+	case tokenEOF:
+		return fmt.Errorf("expected %v at the end of the formula", want)
+	case tokenEnd:
+		return fmt.Errorf("expected %v at the end of %v", want, t.pos)
+	}
+	return fmt.Errorf("expected %v but found '%v' at %v", want, t.text, t.pos)
+}
+
+func (p *parser) parseProgram() error {
+	for {
+		switch p.peek().kind {
+		case tokenEOF:
+			return nil
+		case tokenEnd:
+			p.next()
+			continue
+		}
+
+		if err := p.parseStatement(); err != nil {
+			return err
+		}
+
+		if t := p.peek(); t.kind != tokenEnd && t.kind != tokenEOF {
+			return p.unexpected("a new line or ';'")
+		}
+	}
+}
+
+// parseStatement parses an assignment, like 'v = x * 2'
+func (p *parser) parseStatement() error {
+	name := p.peek()
+	if name.kind != tokenIdent {
+		return p.unexpected("a variable to assign")
+	}
+	p.next()
+	if _, isFunc := functions[strings.ToLower(name.text)]; isFunc {
+		return fmt.Errorf("can't assign to the function '%v' at %v", name.text, name.pos)
+	}
+	if _, isConst := constants[name.text]; isConst {
+		return fmt.Errorf("can't assign to the constant '%v' at %v", name.text, name.pos)
+	}
+
+	if err := p.expect("="); err != nil {
+		return err
+	}
+
+	value, err := p.parseExpr()
+	if err != nil {
+		return err
+	}
+
+	slot := p.program.slot(name.text)
+	p.defined[name.text] = true
+	p.program.assigned[name.text] = true
+	p.program.statements = append(p.program.statements, func(vars []float64) {
+		vars[slot] = value(vars)
+	})
+
+	return nil
+}
+
+func (p *parser) parseExpr() (expr, error) {
+	leave, err := p.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.accept("?") {
+		return cond, nil
+	}
+
+	yes, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	no, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(v []float64) float64 {
+		if cond(v) != 0 {
+			return yes(v)
+		}
+		return no(v)
+	}, nil
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v []float64) float64 { return truth(l(v) != 0 || right(v) != 0) }
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseCompare()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.accept("&&") {
+		right, err := p.parseCompare()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v []float64) float64 { return truth(l(v) != 0 && right(v) != 0) }
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseCompare() (expr, error) {
+	return p.parseBinary(p.parseAdd, map[string]func(a, b float64) float64{
+		"<":  func(a, b float64) float64 { return truth(a < b) },
+		"<=": func(a, b float64) float64 { return truth(a <= b) },
+		">":  func(a, b float64) float64 { return truth(a > b) },
+		">=": func(a, b float64) float64 { return truth(a >= b) },
+		"==": func(a, b float64) float64 { return truth(a == b) },
+		"!=": func(a, b float64) float64 { return truth(a != b) },
+	})
+}
+
+func (p *parser) parseAdd() (expr, error) {
+	return p.parseBinary(p.parseMultiply, map[string]func(a, b float64) float64{
+		"+": func(a, b float64) float64 { return a + b },
+		"-": func(a, b float64) float64 { return a - b },
+	})
+}
+
+func (p *parser) parseMultiply() (expr, error) {
+	return p.parseBinary(p.parseUnary, map[string]func(a, b float64) float64{
+		"*": func(a, b float64) float64 { return a * b },
+		"/": func(a, b float64) float64 { return a / b },
+		"%": remainder,
+	})
+}
+
+// parseBinary parses a left associative run of the passed operators
+func (p *parser) parseBinary(operand func() (expr, error), ops map[string]func(a, b float64) float64) (expr, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		op, found := ops[t.text]
+		if t.kind != tokenOperator || !found {
+			return left, nil
+		}
+		p.next()
+
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v []float64) float64 { return op(l(v), right(v)) }
+	}
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	leave, err := p.enter()
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	switch {
+	case p.accept("-"):
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(v []float64) float64 { return -operand(v) }, nil
+
+	case p.accept("+"):
+		return p.parseUnary()
+
+	case p.accept("!"):
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(v []float64) float64 { return truth(operand(v) == 0) }, nil
+	}
+
+	return p.parsePower()
+}
+
+// parsePower parses x ^ y, which is right associative (2^3^2 is 2^9)
+func (p *parser) parsePower() (expr, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.accept("^") {
+		return base, nil
+	}
+
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(v []float64) float64 { return pow(base(v), exponent(v)) }, nil
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.peek()
+	if t.kind == tokenEOF || t.kind == tokenEnd {
+		return nil, p.unexpected("a number, variable or function")
+	}
+	p.next()
+
+	switch t.kind {
+	case tokenNumber:
+		value := t.value
+		return func([]float64) float64 { return value }, nil
+
+	case tokenIdent:
+		if p.accept("(") {
+			return p.parseCall(t)
+		}
+
+		if value, found := constants[t.text]; found {
+			return func([]float64) float64 { return value }, nil
+		}
+
+		if !p.defined[t.text] {
+			return nil, fmt.Errorf("unknown variable '%v' at %v", t.text, t.pos)
+		}
+
+		slot := p.program.slot(t.text)
+		return func(v []float64) float64 { return v[slot] }, nil
+
+	case tokenOperator:
+		if t.text == "(" {
+			inner, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		}
+	}
+
+	p.pos--
+	return nil, p.unexpected("a number, variable or function")
+}
+
+// parseCall parses the arguments to a function (the opening bracket has been read)
+func (p *parser) parseCall(name token) (expr, error) {
+	f, found := functions[strings.ToLower(name.text)]
+	if !found {
+		return nil, fmt.Errorf("unknown function '%v' at %v", name.text, name.pos)
+	}
+
+	args := []expr{}
+	if !p.accept(")") {
+		for {
+			arg, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+
+			if p.accept(")") {
+				break
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(args) < f.minArgs || len(args) > f.maxArgs {
+		want := fmt.Sprintf("%v", f.minArgs)
+		if f.maxArgs != f.minArgs {
+			want = fmt.Sprintf("%v to %v", f.minArgs, f.maxArgs)
+		}
+		return nil, fmt.Errorf("%v takes %v arguments, not %v at %v", name.text, want, len(args), name.pos)
+	}
+
+	//	Each call has its own scratch space for its arguments, so evaluating
+	//	doesn't allocate.  (This is why a Program can't be run concurrently)
+	call := f.call
+	if f.callRand != nil {
+		program := p.program
+		call = func(a []float64) float64 { return f.callRand(program.rnd, a) }
+	}
+	values := make([]float64, len(args))
+	return func(v []float64) float64 {
+		for i, arg := range args {
+			values[i] = arg(v)
+		}
+		return call(values)
+	}, nil
+}
+
+// truth turns a boolean into 1 or 0
+func truth(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,126 @@
+package formula_test
+
+import (
+	"github.com/danesparza/fxpixel/internal/formula"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestFormulaEvaluate(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		formula string
+		x       float64
+		want    float64
+	}{
+		{name: "Precedence", formula: "v = 1 + 2 * 3", want: 7},
+		{name: "Brackets", formula: "v = (1 + 2) * 3", want: 9},
+		{name: "Power is right associative", formula: "v = 2 ^ 3 ^ 2", want: 512},
+		{name: "Unary minus", formula: "v = -x ^ 2", x: 3, want: -9},
+		{name: "Remainder", formula: "v = -7 % 3", want: -1},
+		{name: "Floored modulo", formula: "v = mod(-7, 3)", want: 2},
+		{name: "Comparisons give 1 or 0", formula: "v = (x > 1) + (x == 2) + (x != 2)", x: 2, want: 2},
+		{name: "Ternary", formula: "v = x < 0.5 ? 10 : 20", x: 0.7, want: 20},
+		{name: "Logic", formula: "v = x > 0 && !(x > 5) || 0", x: 3, want: 1},
+		{name: "Local variables", formula: "a = x * 2; b = a + 1\nv = a * b", x: 2, want: 20},
+		{name: "Outputs can be read back", formula: "v = x; v = v * 3", x: 2, want: 6},
+		{name: "Constants", formula: "v = cos(PI)", want: -1},
+		{name: "Wave", formula: "v = wave(0.25)", want: 1},
+		{name: "Triangle", formula: "v = triangle(x)", x: 1.25, want: 0.5},
+		{name: "Square with duty cycle", formula: "v = square(x, 0.25)", x: 0.3, want: 0},
+		{name: "Variadic max", formula: "v = max(1, x, 3)", x: 5, want: 5},
+		{name: "Clamp", formula: "v = clamp(x, 0, 1)", x: 4, want: 1},
+		{name: "Comments", formula: "// Brightness\nv = 0.5 // half", want: 0.5},
+		{name: "Exponents in numbers", formula: "v = 2.5e2 + .5", want: 250.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := formula.Compile(tt.formula, "x", "v")
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+
+			vars := p.Vars()
+			x, _ := p.Slot("x")
+			v, _ := p.Slot("v")
+			vars[x] = tt.x
+			p.Run(vars)
+
+			if math.Abs(vars[v]-tt.want) > 1e-9 {
+				t.Errorf("v = %v, want %v", vars[v], tt.want)
+			}
+		})
+	}
+}
+
+func TestFormulaErrors(t *testing.T) {
+
+	tests := []struct {
+		name    string
+		formula string
+		want    string
+	}{
+		{name: "Unknown variable", formula: "v = y", want: "unknown variable 'y' at line 1, column 5"},
+		{name: "Variable used before it's assigned", formula: "v = a\na = 1", want: "unknown variable 'a'"},
+		{name: "Unknown function", formula: "v = foo(1)", want: "unknown function 'foo'"},
+		{name: "Wrong number of arguments", formula: "v = sin(1, 2)", want: "sin takes 1 arguments, not 2"},
+		{name: "Missing bracket", formula: "v = (1 + 2", want: "expected ')'"},
+		{name: "Not an assignment", formula: "1 + 2", want: "expected a variable to assign"},
+		{name: "Assigning to a function", formula: "sin = 2", want: "can't assign to the function"},
+		{name: "Bad character", formula: "v = 1 $ 2", want: "unexpected '$' at line 1, column 7"},
+		{name: "Error on a later line", formula: "v = 1\nv = v +", want: "at the end of the formula"},
+		{name: "Nested too deeply", formula: "v = " + strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100), want: "nested more than 64 deep"},
+		{name: "Too many unary operators", formula: "v = " + strings.Repeat("-", 100) + "1", want: "nested more than 64 deep"},
+		{name: "Too long", formula: "v = 1" + strings.Repeat(" + 1", 2000), want: "longer than 4096 characters"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := formula.Compile(tt.formula, "x", "v")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Compile() error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormulaAssigns(t *testing.T) {
+	p, err := formula.Compile("r = x; g = 1", "x", "r", "g", "b")
+	if err != nil {
+		t.Fatalf("Compile() error = %v", err)
+	}
+
+	if !p.Assigns("r") || !p.Assigns("g") || p.Assigns("b") || p.Assigns("x") {
+		t.Errorf("Assigns() doesn't match the formula")
+	}
+}
+
+func TestFormulaRandom(t *testing.T) {
+	run := func(seed int64) []float64 {
+		p, err := formula.Compile("v = random(10)", "v")
+		if err != nil {
+			t.Fatalf("Compile() error = %v", err)
+		}
+		p.SetRand(rand.New(rand.NewSource(seed)))
+
+		vars := p.Vars()
+		slot, _ := p.Slot("v")
+		retval := []float64{}
+		for i := 0; i < 5; i++ {
+			p.Run(vars)
+			retval = append(retval, vars[slot])
+		}
+		return retval
+	}
+
+	first, second := run(7), run(7)
+	for i := range first {
+		if first[i] != second[i] || first[i] < 0 || first[i] >= 10 {
+			t.Errorf("random(10) = %v then %v, want the same value (0 - 10) with the same seed", first[i], second[i])
+		}
+	}
+}
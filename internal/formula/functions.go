@@ -0,0 +1,111 @@
+package formula
+
+import (
+	"math"
+	"math/rand"
+)
+
+type function struct {
+	minArgs  int
+	maxArgs  int
+	call     func(args []float64) float64
+	callRand func(rnd *rand.Rand, args []float64) float64 // For functions that need the program's random numbers
+}
+
+// unary wraps a single argument math function
+func unary(f func(float64) float64) function {
+	return function{minArgs: 1, maxArgs: 1, call: func(a []float64) float64 { return f(a[0]) }}
+}
+
+// binary wraps a two argument math function
+func binary(f func(a, b float64) float64) function {
+	return function{minArgs: 2, maxArgs: 2, call: func(a []float64) float64 { return f(a[0], a[1]) }}
+}
+
+var constants = map[string]float64{
+	"PI":  math.Pi,
+	"TAU": 2 * math.Pi,
+	"E":   math.E,
+}
+
+var functions = map[string]function{
+	"sin":   unary(math.Sin),
+	"cos":   unary(math.Cos),
+	"tan":   unary(math.Tan),
+	"asin":  unary(math.Asin),
+	"acos":  unary(math.Acos),
+	"atan":  unary(math.Atan),
+	"atan2": binary(math.Atan2),
+	"abs":   unary(math.Abs),
+	"floor": unary(math.Floor),
+	"ceil":  unary(math.Ceil),
+	"round": unary(math.Round),
+	"frac":  unary(frac),
+	"sqrt":  unary(math.Sqrt),
+	"exp":   unary(math.Exp),
+	"log":   unary(math.Log),
+	"pow":   binary(pow),
+	"mod":   binary(mod),
+	"hypot": binary(math.Hypot),
+	"min": {minArgs: 2, maxArgs: 8, call: func(a []float64) float64 {
+		retval := a[0]
+		for _, v := range a[1:] {
+			retval = math.Min(retval, v)
+		}
+		return retval
+	}},
+	"max": {minArgs: 2, maxArgs: 8, call: func(a []float64) float64 {
+		retval := a[0]
+		for _, v := range a[1:] {
+			retval = math.Max(retval, v)
+		}
+		return retval
+	}},
+	"clamp": {minArgs: 3, maxArgs: 3, call: func(a []float64) float64 { return math.Max(a[1], math.Min(a[2], a[0])) }},
+	"mix":   {minArgs: 3, maxArgs: 3, call: func(a []float64) float64 { return a[0] + (a[1]-a[0])*a[2] }},
+	"smoothstep": {minArgs: 3, maxArgs: 3, call: func(a []float64) float64 {
+		if a[1] == a[0] {
+			return truth(a[2] >= a[1])
+		}
+		t := math.Max(0, math.Min(1, (a[2]-a[0])/(a[1]-a[0])))
+		return t * t * (3 - 2*t)
+	}},
+
+	//	Waveforms take a phase (0 - 1 is one cycle) and give 0 - 1
+	"wave":     unary(func(x float64) float64 { return (1 + math.Sin(x*2*math.Pi)) / 2 }),
+	"triangle": unary(func(x float64) float64 { return 1 - math.Abs(frac(x)*2-1) }),
+	"square": {minArgs: 1, maxArgs: 2, call: func(a []float64) float64 {
+		duty := 0.5
+		if len(a) > 1 {
+			duty = a[1]
+		}
+		return truth(frac(a[0]) < duty)
+	}},
+
+	//	random() is 0 - 1, random(n) is 0 - n
+	"random": {minArgs: 0, maxArgs: 1, callRand: func(rnd *rand.Rand, a []float64) float64 {
+		if len(a) == 0 {
+			return rnd.Float64()
+		}
+		return rnd.Float64() * a[0]
+	}},
+}
+
+func frac(x float64) float64 {
+	return x - math.Floor(x)
+}
+
+// remainder is the % operator.  The result has the sign of the left side (like C)
+func remainder(a, b float64) float64 {
+	return math.Mod(a, b)
+}
+
+// mod is a floored modulo.  The result has the sign of the right side,
+// which is usually what you want for wrapping positions
+func mod(a, b float64) float64 {
+	return a - b*math.Floor(a/b)
+}
+
+func pow(a, b float64) float64 {
+	return math.Pow(a, b)
+}
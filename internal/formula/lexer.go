@@ -0,0 +1,140 @@
+package formula
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenEnd // End of a statement (';' or a new line)
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   position
+}
+
+// position is where a token was found in the source, for error messages
+type position struct {
+	line   int
+	column int
+}
+
+func (p position) String() string {
+	return fmt.Sprintf("line %v, column %v", p.line, p.column)
+}
+
+// operators are checked longest first, so '<=' wins over '<'
+var operators = []string{
+	"&&", "||", "==", "!=", "<=", ">=",
+	"+", "-", "*", "/", "%", "^", "<", ">", "!", "=", "?", ":", "(", ")", ",",
+}
+
+// lex splits the source into tokens
+func lex(src string) ([]token, error) {
+	retval := []token{}
+	pos := position{line: 1, column: 1}
+
+	for i := 0; i < len(src); {
+		c := rune(src[i])
+		start := pos
+
+		switch {
+		case c == '\n' || c == ';':
+			retval = append(retval, token{kind: tokenEnd, text: string(c), pos: start})
+			i++
+			if c == '\n' {
+				pos.line++
+				pos.column = 1
+			} else {
+				pos.column++
+			}
+			continue
+
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			pos.column++
+			continue
+
+		case strings.HasPrefix(src[i:], "//"):
+			//	Comments run to the end of the line
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+			continue
+
+		case isDigit(src[i]) || (c == '.' && i+1 < len(src) && isDigit(src[i+1])):
+			j := i
+			for j < len(src) && (isDigit(src[j]) || src[j] == '.') {
+				j++
+			}
+			//	Exponents, like 1e-3
+			if j < len(src) && (src[j] == 'e' || src[j] == 'E') {
+				k := j + 1
+				if k < len(src) && (src[k] == '+' || src[k] == '-') {
+					k++
+				}
+				if k < len(src) && isDigit(src[k]) {
+					for k < len(src) && isDigit(src[k]) {
+						k++
+					}
+					j = k
+				}
+			}
+
+			value, err := strconv.ParseFloat(src[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("bad number '%v' at %v", src[i:j], start)
+			}
+			retval = append(retval, token{kind: tokenNumber, text: src[i:j], value: value, pos: start})
+			pos.column += j - i
+			i = j
+			continue
+
+		case isLetter(src[i]):
+			j := i
+			for j < len(src) && (isLetter(src[j]) || isDigit(src[j])) {
+				j++
+			}
+			retval = append(retval, token{kind: tokenIdent, text: src[i:j], pos: start})
+			pos.column += j - i
+			i = j
+			continue
+		}
+
+		found := false
+		for _, op := range operators {
+			if strings.HasPrefix(src[i:], op) {
+				retval = append(retval, token{kind: tokenOperator, text: op, pos: start})
+				i += len(op)
+				pos.column += len(op)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("unexpected '%c' at %v", c, start)
+		}
+	}
+
+	retval = append(retval, token{kind: tokenEOF, pos: pos})
+	return retval, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -110,19 +110,6 @@ type Artist interface {
 	Draw(*pixarray.PixArray)
 }
 
-type Gradient struct {
-	Colors [][]int `json:"colors"` // Pass 3 colors (or 4 when using RGBW strips)
-}
-
-func (g *Gradient) Draw(arr *pixarray.PixArray) {
-	c1 := color(g.Colors[0][:])
-	c2 := color(g.Colors[1][:])
-	for i := 0; i < arr.NumPixels(); i++ {
-		t := float32(i) / float32(arr.NumPixels())
-		arr.SetOne(i, lerp(c1, c2, t))
-	}
-}
-
 type Sequence struct {
 	Colors [][]int `json:"colors"` // Pass 3 colors (or 4 when using RGBW strips)
 }
@@ -149,7 +136,7 @@ func NewKnightRider(pulseTime time.Duration, pulseLen int) *KnightRider {
 
 func (kr *KnightRider) Start(pa *pixarray.PixArray, now time.Time) {
 	kr.start = now
-	pa.SetAll(pixarray.Pixel{0, 0, 0, 0})
+	pa.SetAll(pixarray.Pixel{})
 }
 
 func (kr *KnightRider) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
@@ -179,7 +166,7 @@ func (kr *KnightRider) NextStep(pa *pixarray.PixArray, now time.Time) time.Durat
 	}
 	for i := pulseTail; i != rangeHead; i = i + pulseDir {
 		v := int((float64(kr.pulseLen-abs(pulseHead-i))/float64(kr.pulseLen))*126.0) + 1
-		pa.SetOne(i, pixarray.Pixel{v, 0, 0, 0})
+		pa.SetOne(i, pixarray.Pixel{R: v})
 	}
 	return time.Millisecond
 }
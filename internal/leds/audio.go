@@ -0,0 +1,203 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/audio"
+	"github.com/danesparza/fxpixel/internal/data"
+	"math"
+	"time"
+)
+
+// vuColors is the default palette for audio level meters
+var vuColors = []data.MetaColor{
+	{G: 255},
+	{R: 255, G: 255},
+	{R: 255},
+}
+
+// clamp01 keeps f in the range 0 - 1
+func clamp01(f float64) float64 {
+	return math.Max(0, math.Min(1, f))
+}
+
+// VUMeter shows the audio level as a bar along the strip
+type VUMeter struct {
+	input       audio.Input
+	colors      palette
+	peakColor   pixarray.Pixel
+	mirror      bool
+	sensitivity float64
+	peak        float64
+	last        time.Time
+}
+
+// NewVUMeter creates a level meter effect.  Mirror grows the bar out from the
+// middle of the strip, and sensitivity scales the audio level
+func NewVUMeter(input audio.Input, colors palette, peakColor pixarray.Pixel, mirror bool, sensitivity float64) *VUMeter {
+	vu := VUMeter{}
+	vu.input = input
+	vu.colors = colors
+	vu.peakColor = peakColor
+	vu.mirror = mirror
+	vu.sensitivity = sensitivity
+	return &vu
+}
+
+func (vu *VUMeter) Start(pa *pixarray.PixArray, now time.Time) {
+	vu.last = now
+	vu.peak = 0
+}
+
+func (vu *VUMeter) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	dt := now.Sub(vu.last).Seconds()
+	vu.last = now
+
+	level := clamp01(vu.input.Latest().Level * vu.sensitivity)
+
+	//	The peak marker jumps up with the level, then falls slowly
+	vu.peak = math.Max(level, vu.peak-dt*0.5)
+
+	length := pa.NumPixels()
+	if vu.mirror {
+		length = (length + 1) / 2
+	}
+
+	//	Set a pixel (and its mirror image, if we're mirrored)
+	set := func(i int, c pixarray.Pixel) {
+		if !vu.mirror {
+			pa.SetOne(i, c)
+			return
+		}
+		pa.SetOne((pa.NumPixels()-1)/2-i, c)
+		pa.SetOne(pa.NumPixels()/2+i, c)
+	}
+
+	pa.SetAll(pixarray.Pixel{})
+
+	lit := level * float64(length)
+	for i := 0; i < length && float64(i) < lit; i++ {
+		c := vu.colors.colorAt(float64(i) / math.Max(1, float64(length-1)))
+		if float64(i+1) > lit {
+			c = Scale(c, float32(frac(lit)))
+		}
+		set(i, c)
+	}
+
+	if vu.peakColor != (pixarray.Pixel{}) && vu.peak > 0 {
+		set(int(vu.peak*float64(length-1)), vu.peakColor)
+	}
+
+	return time.Millisecond
+}
+
+func (vu *VUMeter) Name() string {
+	return "VUMETER"
+}
+
+// Spectrum shows the loudness of each frequency band.  On a strip, each band gets
+// a section of the strip.  On a matrix, each band is a bar
+type Spectrum struct {
+	input       audio.Input
+	colors      palette
+	sensitivity float64
+	matrix      Matrix
+}
+
+// NewSpectrum creates a spectrum analyzer effect.  Sensitivity scales the band levels
+func NewSpectrum(input audio.Input, colors palette, sensitivity float64, matrix Matrix) *Spectrum {
+	s := Spectrum{}
+	s.input = input
+	s.colors = colors
+	s.sensitivity = sensitivity
+	s.matrix = matrix
+	return &s
+}
+
+func (s *Spectrum) Start(pa *pixarray.PixArray, now time.Time) {
+}
+
+func (s *Spectrum) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	frame := s.input.Latest()
+	pa.SetAll(pixarray.Pixel{})
+
+	bandColor := func(band int) pixarray.Pixel {
+		return s.colors.colorAt(float64(band) / (audio.Bands - 1))
+	}
+
+	if !s.matrix.IsSet() {
+		for i := 0; i < pa.NumPixels(); i++ {
+			band := i * audio.Bands / pa.NumPixels()
+			pa.SetOne(i, Scale(bandColor(band), float32(clamp01(frame.Bands[band]*s.sensitivity))))
+		}
+		return time.Millisecond
+	}
+
+	//	Draw a bar for each band, growing up from the bottom row
+	for x := 0; x < s.matrix.Width; x++ {
+		band := x * audio.Bands / s.matrix.Width
+		height := int(math.Round(clamp01(frame.Bands[band]*s.sensitivity) * float64(s.matrix.Height)))
+		for y := s.matrix.Height - height; y < s.matrix.Height; y++ {
+			if i := s.matrix.Index(x, y); i < pa.NumPixels() {
+				pa.SetOne(i, bandColor(band))
+			}
+		}
+	}
+
+	return time.Millisecond
+}
+
+func (s *Spectrum) Name() string {
+	return "SPECTRUM"
+}
+
+// BeatPulse flashes the strip on each beat, stepping to the next color each time
+type BeatPulse struct {
+	input      audio.Input
+	colors     palette
+	decay      time.Duration
+	beats      int
+	color      int
+	pulseStart time.Time
+}
+
+// NewBeatPulse creates a beat pulse effect.  Decay is the time for each pulse to fade out
+func NewBeatPulse(input audio.Input, colors palette, decay time.Duration) *BeatPulse {
+	bp := BeatPulse{}
+	bp.input = input
+	bp.colors = colors
+	bp.decay = decay
+	return &bp
+}
+
+func (bp *BeatPulse) Start(pa *pixarray.PixArray, now time.Time) {
+	//	Only pulse for beats that happen from here on
+	bp.beats = bp.input.Latest().Beats
+	bp.color = -1
+}
+
+func (bp *BeatPulse) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	if len(bp.colors.stops) == 0 {
+		return 0
+	}
+
+	frame := bp.input.Latest()
+	if frame.Beats != bp.beats {
+		bp.beats = frame.Beats
+		bp.color = (bp.color + 1) % len(bp.colors.stops)
+		bp.pulseStart = now
+	}
+
+	if bp.color < 0 {
+		pa.SetAll(pixarray.Pixel{})
+		return time.Millisecond
+	}
+
+	brightness := 1 - clamp01(float64(now.Sub(bp.pulseStart))/float64(bp.decay))
+	pa.SetAll(Scale(bp.colors.stops[bp.color].color, float32(brightness*brightness)))
+
+	return time.Millisecond
+}
+
+func (bp *BeatPulse) Name() string {
+	return "BEATPULSE"
+}
@@ -0,0 +1,87 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"math"
+	"time"
+)
+
+// BeaconSegment is a set of leds that steps through a pattern of colors
+type BeaconSegment struct {
+	Leds    string           // Leds in the segment (for example "0-74").  Blank means the entire strip
+	Colors  []pixarray.Pixel // Colors the segment steps through, one per phase of the pattern
+	Flashes int              // Number of quick flashes for each color.  0 means a steady color
+	Offset  time.Duration    // How far into the pattern this segment starts
+	Rotate  bool             // Sweep the color around the segment like a rotating beacon
+	pixels  []int
+}
+
+// Beacon plays emergency style light patterns across one or more segments of the strip
+type Beacon struct {
+	period   time.Duration
+	segments []BeaconSegment
+	start    time.Time
+}
+
+// NewBeacon creates a beacon effect.  Period is the time for one full pass through the pattern
+func NewBeacon(period time.Duration, segments []BeaconSegment) *Beacon {
+	b := Beacon{}
+	b.period = period
+	b.segments = segments
+	return &b
+}
+
+func (b *Beacon) Start(pa *pixarray.PixArray, now time.Time) {
+	b.start = now
+
+	//	Figure out which pixels belong to each segment
+	for i := range b.segments {
+		b.segments[i].pixels = parseLedRange(b.segments[i].Leds, pa.NumPixels())
+	}
+}
+
+func (b *Beacon) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	pa.SetAll(pixarray.Pixel{})
+
+	for _, s := range b.segments {
+		if len(s.Colors) == 0 || len(s.pixels) == 0 {
+			continue
+		}
+
+		//	Find where we are in the pattern
+		pos := frac(float64(now.Sub(b.start)+s.Offset) / float64(b.period))
+		phasePos := pos * float64(len(s.Colors))
+		phase := int(phasePos)
+		progress := frac(phasePos)
+		c := s.Colors[phase]
+
+		//	Rotating beacons sweep a bright spot around the segment
+		if s.Rotate {
+			width := float64(len(s.pixels)) / 4
+			head := pos * float64(len(s.pixels))
+			for i, p := range s.pixels {
+				dist := math.Abs(float64(i) - head)
+				dist = math.Min(dist, float64(len(s.pixels))-dist)
+				if dist < width {
+					pa.SetOne(p, Scale(c, float32(1-dist/width)))
+				}
+			}
+			continue
+		}
+
+		//	Flashing segments split each phase into on/off slots
+		if s.Flashes > 0 && int(progress*float64(s.Flashes*2))%2 == 1 {
+			continue
+		}
+
+		for _, p := range s.pixels {
+			pa.SetOne(p, c)
+		}
+	}
+
+	return time.Millisecond
+}
+
+func (b *Beacon) Name() string {
+	return "BEACON"
+}
@@ -0,0 +1,121 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ball tracks the state of a single bouncing ball.  Height is measured
+// in strip lengths from the start of the strip (the 'floor')
+type ball struct {
+	color     pixarray.Pixel
+	height    float64
+	velocity  float64
+	dampening float64
+	resting   bool
+}
+
+// BouncingBalls drops a set of balls that fall under gravity and bounce,
+// losing some of their speed with each bounce
+type BouncingBalls struct {
+	colors    []pixarray.Pixel
+	gravity   float64
+	dampening float64
+	relaunch  bool
+	balls     []ball
+	last      time.Time
+	rnd       *rand.Rand
+}
+
+// NewBouncingBalls creates a bouncing balls effect with a ball for each of the passed colors.
+// Gravity is in strip lengths per second squared, and dampening is the portion (0 - 1)
+// of a ball's speed that is lost with each bounce.  Rnd picks the relaunch speeds
+func NewBouncingBalls(colors []pixarray.Pixel, gravity, dampening float64, relaunch bool, rnd *rand.Rand) *BouncingBalls {
+	bb := BouncingBalls{}
+	bb.colors = colors
+	bb.gravity = gravity
+	bb.dampening = dampening
+	bb.relaunch = relaunch
+	bb.rnd = rnd
+	return &bb
+}
+
+// launchSpeed is the speed a ball needs to reach the top of the strip
+func (bb *BouncingBalls) launchSpeed() float64 {
+	return math.Sqrt(2 * bb.gravity)
+}
+
+func (bb *BouncingBalls) Start(pa *pixarray.PixArray, now time.Time) {
+	bb.last = now
+
+	//	Drop all the balls from the top, each with a slightly
+	//	different dampening so they fall out of step with each other
+	bb.balls = make([]ball, len(bb.colors))
+	for i, c := range bb.colors {
+		bb.balls[i] = ball{
+			color:     c,
+			height:    1,
+			dampening: bb.dampening + (float64(i)/float64(len(bb.colors)))*bb.dampening*0.5,
+		}
+	}
+}
+
+func (bb *BouncingBalls) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	dt := now.Sub(bb.last).Seconds()
+	bb.last = now
+
+	//	Speeds below this are considered 'at rest'
+	restSpeed := bb.launchSpeed() * 0.05
+	allResting := true
+
+	pa.SetAll(pixarray.Pixel{})
+
+	for i := range bb.balls {
+		b := &bb.balls[i]
+
+		if !b.resting {
+			b.velocity -= bb.gravity * dt
+			b.height += b.velocity * dt
+
+			//	Bounce off the floor
+			if b.height <= 0 {
+				b.height = 0
+				b.velocity = -b.velocity * (1 - b.dampening)
+
+				if b.velocity < restSpeed {
+					b.velocity = 0
+					b.resting = true
+				}
+			}
+		}
+
+		//	Throw resting balls back up, if we've been asked to
+		if b.resting && bb.relaunch {
+			b.resting = false
+			b.velocity = bb.launchSpeed() * (0.8 + bb.rnd.Float64()*0.2)
+		}
+
+		if !b.resting {
+			allResting = false
+		}
+
+		pos := round(b.height * float64(pa.NumPixels()-1))
+		if pos >= pa.NumPixels() {
+			pos = pa.NumPixels() - 1
+		}
+		pa.SetOne(pos, b.color)
+	}
+
+	//	Once everything has settled, we're done
+	if allResting {
+		return 0
+	}
+
+	return time.Millisecond
+}
+
+func (bb *BouncingBalls) Name() string {
+	return "BOUNCINGBALLS"
+}
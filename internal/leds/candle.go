@@ -0,0 +1,106 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"math/rand"
+	"time"
+)
+
+// flame tracks the state of a single flickering candle
+type flame struct {
+	level  float64   // Current brightness (0 - 1)
+	target float64   // Brightness the flame is moving towards
+	next   time.Time // When the flame should pick a new target
+}
+
+// Candle simulates a set of independently flickering candle flames.
+// Each group of pixels is its own candle, and wind gusts
+// occasionally disturb all of them at once
+type Candle struct {
+	temperature int
+	brightness  float64
+	intensity   float64
+	wind        float64
+	groupSize   int
+	flames      []flame
+	gust        float64
+	gustUntil   time.Time
+	last        time.Time
+	rnd         *rand.Rand
+}
+
+// NewCandle creates a candle effect.  Temperature is the base color temperature (in Kelvin),
+// brightness is 0 - 255 and intensity and wind are 0 - 1.  Rnd drives the flicker
+func NewCandle(temperature, brightness int, intensity, wind float64, groupSize int, rnd *rand.Rand) *Candle {
+	c := Candle{}
+	c.temperature = temperature
+	c.brightness = float64(brightness) / 255.0
+	c.intensity = intensity
+	c.wind = wind
+	c.groupSize = groupSize
+	c.rnd = rnd
+	return &c
+}
+
+func (c *Candle) Start(pa *pixarray.PixArray, now time.Time) {
+	c.last = now
+
+	//	Light a flame for each group of pixels
+	numFlames := (pa.NumPixels() + c.groupSize - 1) / c.groupSize
+	c.flames = make([]flame, numFlames)
+	for i := range c.flames {
+		c.flames[i] = flame{level: 1, target: 1, next: now}
+	}
+}
+
+func (c *Candle) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	dt := now.Sub(c.last).Seconds()
+	c.last = now
+
+	//	See if a gust of wind is blowing (or should start to)
+	if now.After(c.gustUntil) {
+		c.gust = 0
+		if c.rnd.Float64() < c.wind*dt*0.5 {
+			c.gust = c.wind * (0.5 + c.rnd.Float64()*0.5)
+			c.gustUntil = now.Add(time.Duration(300+c.rnd.Intn(1200)) * time.Millisecond)
+		}
+	}
+
+	//	Move each flame towards its target brightness
+	smoothing := dt * (15 + c.gust*30)
+	if smoothing > 1 {
+		smoothing = 1
+	}
+
+	for i := range c.flames {
+		f := &c.flames[i]
+
+		if !now.Before(f.next) {
+			//	Dip the flame a random amount.  Gusts make the dips deeper and quicker
+			f.target = 1 - c.rnd.Float64()*c.intensity*(1+c.gust*2)
+			if f.target < 0.1 {
+				f.target = 0.1
+			}
+
+			wait := 30 + c.rnd.Intn(120)
+			if c.gust > 0 {
+				wait = wait / 2
+			}
+			f.next = now.Add(time.Duration(wait) * time.Millisecond)
+		}
+
+		f.level += (f.target - f.level) * smoothing
+
+		//	Dimmer flames burn redder
+		p := Scale(kelvinToPixel(c.temperature-int((1-f.level)*600)), float32(c.brightness*f.level))
+		for j := i * c.groupSize; j < (i+1)*c.groupSize && j < pa.NumPixels(); j++ {
+			pa.SetOne(j, p)
+		}
+	}
+
+	return time.Millisecond
+}
+
+func (c *Candle) Name() string {
+	return "CANDLE"
+}
@@ -0,0 +1,134 @@
+package leds
+
+import (
+	"fmt"
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/data"
+	"sort"
+	"time"
+)
+
+// circadianInterval is how often the circadian light is worked out again
+const circadianInterval = time.Minute
+
+// minutesPerDay is the length of the circadian curve
+const minutesPerDay = 24 * 60
+
+// defaultCircadianKeyframes is a typical day: warm and dim at night, cool and
+// bright through the middle of the day
+var defaultCircadianKeyframes = []data.CircadianKeyframe{
+	{Time: "00:00", Temperature: 1900, Brightness: 20},
+	{Time: "06:00", Temperature: 2200, Brightness: 40},
+	{Time: "08:00", Temperature: 4000, Brightness: 200},
+	{Time: "12:00", Temperature: 5500, Brightness: 255},
+	{Time: "17:00", Temperature: 4500, Brightness: 220},
+	{Time: "20:00", Temperature: 2700, Brightness: 120},
+	{Time: "22:00", Temperature: 2000, Brightness: 50},
+}
+
+// circadianPoint is a keyframe, ready to blend
+type circadianPoint struct {
+	minute     float64 // Minutes after midnight
+	mired      float64 // Color temperature, in mireds
+	brightness float64 // Brightness (0 - 1)
+}
+
+// Circadian follows the time of day, blending the color temperature and brightness
+// smoothly from one keyframe to the next
+type Circadian struct {
+	points []circadianPoint
+	white  bool
+	next   time.Time
+}
+
+// NewCircadian creates a circadian effect from keyframes.  If white is set, the white
+// channel is used for the white part of the color
+func NewCircadian(keyframes []data.CircadianKeyframe, white bool) (*Circadian, error) {
+	c := Circadian{}
+	c.white = white
+
+	if err := data.CheckKeyframes(keyframes); err != nil {
+		return nil, err
+	}
+
+	for _, k := range keyframes {
+		t, _ := time.Parse("15:04", k.Time)
+
+		c.points = append(c.points, circadianPoint{
+			minute:     float64(t.Hour()*60 + t.Minute()),
+			mired:      1e6 / float64(k.Temperature),
+			brightness: float64(clampChannel(float64(k.Brightness))) / 255.0,
+		})
+	}
+
+	if len(c.points) == 0 {
+		return nil, fmt.Errorf("there aren't any keyframes")
+	}
+
+	sort.SliceStable(c.points, func(i, j int) bool {
+		return c.points[i].minute < c.points[j].minute
+	})
+
+	return &c, nil
+}
+
+func (c *Circadian) Start(pa *pixarray.PixArray, now time.Time) {
+	c.next = now
+}
+
+func (c *Circadian) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	if now.Before(c.next) {
+		return c.next.Sub(now)
+	}
+	c.next = now.Add(circadianInterval)
+
+	local := now.Local()
+	minute := float64(local.Hour()*60+local.Minute()) + float64(local.Second())/60
+	p := c.at(minute)
+
+	color := data.KelvinColor(round(1e6 / p.mired))
+	if c.white {
+		color = whiteBalance(color)
+	}
+
+	pa.SetAll(Scale(pixarray.Pixel{R: color.R, G: color.G, B: color.B, W: color.W}, float32(p.brightness)))
+
+	return circadianInterval
+}
+
+func (c *Circadian) Name() string {
+	return "CIRCADIAN"
+}
+
+// at returns the light at the passed minute of the day, easing between the keyframes
+// on either side (and wrapping around midnight)
+func (c *Circadian) at(minute float64) circadianPoint {
+	n := len(c.points)
+
+	//	Find the last keyframe at or before this minute (which might be yesterday's last one)
+	prev := n - 1
+	for i, p := range c.points {
+		if p.minute <= minute {
+			prev = i
+		}
+	}
+	a, b := c.points[prev], c.points[(prev+1)%n]
+
+	span := b.minute - a.minute
+	if span <= 0 {
+		span += minutesPerDay
+	}
+	since := minute - a.minute
+	if since < 0 {
+		since += minutesPerDay
+	}
+
+	t := clamp01(since / span)
+	t = t * t * (3 - 2*t)
+
+	return circadianPoint{
+		minute:     minute,
+		mired:      a.mired + (b.mired-a.mired)*t,
+		brightness: a.brightness + (b.brightness-a.brightness)*t,
+	}
+}
@@ -0,0 +1,62 @@
+package leds
+
+import (
+	"github.com/danesparza/fxpixel/internal/data"
+	"math"
+	"testing"
+)
+
+func TestCircadianAt(t *testing.T) {
+	//	Passed out of order, to make sure they're sorted
+	c, err := NewCircadian([]data.CircadianKeyframe{
+		{Time: "18:00", Temperature: 2500, Brightness: 51},
+		{Time: "06:00", Temperature: 5000, Brightness: 255},
+	}, false)
+	if err != nil {
+		t.Fatalf("NewCircadian error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		minute     float64
+		mired      float64
+		brightness float64
+	}{
+		{name: "Morning keyframe", minute: 6 * 60, mired: 200, brightness: 1},
+		{name: "Midday", minute: 12 * 60, mired: 300, brightness: 0.6},
+		{name: "Evening keyframe", minute: 18 * 60, mired: 400, brightness: 0.2},
+		{name: "Eases into the evening", minute: 21 * 60, mired: 368.75, brightness: 0.325},
+		{name: "Wraps around midnight", minute: 0, mired: 300, brightness: 0.6},
+		{name: "Before the first keyframe", minute: 3 * 60, mired: 231.25, brightness: 0.875},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := c.at(tt.minute)
+			if math.Abs(got.mired-tt.mired) > 1e-9 || math.Abs(got.brightness-tt.brightness) > 1e-9 {
+				t.Errorf("at(%v) = %v mireds at %v, want %v mireds at %v", tt.minute, got.mired, got.brightness, tt.mired, tt.brightness)
+			}
+		})
+	}
+}
+
+func TestNewCircadian(t *testing.T) {
+	tests := []struct {
+		name      string
+		keyframes []data.CircadianKeyframe
+		wantErr   bool
+	}{
+		{name: "Default keyframes", keyframes: defaultCircadianKeyframes},
+		{name: "Single keyframe", keyframes: []data.CircadianKeyframe{{Time: "12:00", Temperature: 4000, Brightness: 255}}},
+		{name: "No keyframes", keyframes: nil, wantErr: true},
+		{name: "Bad keyframe", keyframes: []data.CircadianKeyframe{{Time: "noon", Temperature: 4000}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCircadian(tt.keyframes, false); (err != nil) != tt.wantErr {
+				t.Errorf("NewCircadian error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package leds
+
+import (
+	"fmt"
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"math"
+	"time"
+)
+
+// ringIndex returns the led at position pos (0 - 1) around a ring of n leds.  Offset is the
+// led at the top of the ring (it can be negative), and reverse runs the ring counter-clockwise
+func ringIndex(pos float64, n, offset int, reverse bool) int {
+	i := int(math.Floor(frac(pos)*float64(n))) % n
+	if reverse {
+		i = (n - i) % n
+	}
+	return ((i+offset)%n + n) % n
+}
+
+// ClockColors are the colors for each part of the clock
+type ClockColors struct {
+	Hour   pixarray.Pixel
+	Minute pixarray.Pixel
+	Second pixarray.Pixel
+	Marker pixarray.Pixel // Hour markers on a ring.  Black means no markers
+}
+
+// Clock shows the time.  On a matrix it shows digits, and on a strip it shows
+// hour, minute and second hands around a ring (like a 60 led ring clock)
+type Clock struct {
+	colors   ClockColors
+	twelve   bool
+	font     bitmapFont
+	location *time.Location
+	matrix   Matrix
+	offset   int
+	reverse  bool
+}
+
+// NewClock creates a clock effect.  For rings, offset is the led at 12 o'clock and
+// reverse runs the hands counter-clockwise
+func NewClock(colors ClockColors, twelveHour bool, font bitmapFont, location *time.Location, matrix Matrix, offset int, reverse bool) *Clock {
+	c := Clock{}
+	c.colors = colors
+	c.twelve = twelveHour
+	c.font = font
+	c.location = location
+	c.matrix = matrix
+	c.offset = offset
+	c.reverse = reverse
+	return &c
+}
+
+func (c *Clock) Start(pa *pixarray.PixArray, now time.Time) {
+}
+
+func (c *Clock) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	now = now.In(c.location)
+	pa.SetAll(pixarray.Pixel{})
+
+	if c.matrix.IsSet() {
+		c.drawDigits(pa, now)
+	} else {
+		c.drawRing(pa, now)
+	}
+
+	return time.Millisecond
+}
+
+// drawRing draws the clock hands around a ring
+func (c *Clock) drawRing(pa *pixarray.PixArray, now time.Time) {
+	n := pa.NumPixels()
+	if n == 0 {
+		return
+	}
+
+	if c.colors.Marker != (pixarray.Pixel{}) {
+		for h := 0; h < 12; h++ {
+			pa.SetOne(ringIndex(float64(h)/12, n, c.offset, c.reverse), c.colors.Marker)
+		}
+	}
+
+	seconds := float64(now.Second()) + float64(now.Nanosecond())/1e9
+	minutes := float64(now.Minute()) + seconds/60
+	hours := float64(now.Hour()%12) + minutes/60
+
+	//	Draw the hands from slowest to fastest, so the faster hands are on top
+	pa.SetOne(ringIndex(hours/12, n, c.offset, c.reverse), c.colors.Hour)
+	pa.SetOne(ringIndex(minutes/60, n, c.offset, c.reverse), c.colors.Minute)
+	pa.SetOne(ringIndex(seconds/60, n, c.offset, c.reverse), c.colors.Second)
+}
+
+// drawDigits draws the time as digits on the matrix
+func (c *Clock) drawDigits(pa *pixarray.PixArray, now time.Time) {
+	hour := now.Hour()
+	hourText := fmt.Sprintf("%02d", hour)
+	if c.twelve {
+		hour = hour % 12
+		if hour == 0 {
+			hour = 12
+		}
+		hourText = fmt.Sprintf("%d", hour)
+	}
+
+	hours := renderText(c.font, hourText)
+	colon := renderText(c.font, ":")
+	minutes := renderText(c.font, fmt.Sprintf("%02d", now.Minute()))
+
+	hourColor := func(x int) pixarray.Pixel { return c.colors.Hour }
+	minuteColor := func(x int) pixarray.Pixel { return c.colors.Minute }
+	secondColor := func(x int) pixarray.Pixel { return c.colors.Second }
+
+	width := len(hours) + 1 + len(colon) + 1 + len(minutes)
+
+	//	If it won't fit on one line but there's room, stack the hours on top of the minutes
+	if width > c.matrix.Width && c.matrix.Height >= c.font.height*2+1 {
+		y0 := (c.matrix.Height - (c.font.height*2 + 1)) / 2
+		drawText(pa, c.matrix, hours, (c.matrix.Width-len(hours))/2, y0, hourColor)
+		drawText(pa, c.matrix, minutes, (c.matrix.Width-len(minutes))/2, y0+c.font.height+1, minuteColor)
+		return
+	}
+
+	x0 := (c.matrix.Width - width) / 2
+	y0 := (c.matrix.Height - c.font.height) / 2
+	drawText(pa, c.matrix, hours, x0, y0, hourColor)
+	drawText(pa, c.matrix, minutes, x0+len(hours)+1+len(colon)+1, y0, minuteColor)
+
+	//	Blink the colon every second
+	if now.Second()%2 == 0 {
+		drawText(pa, c.matrix, colon, x0+len(hours)+1, y0, secondColor)
+	}
+
+	//	If there's room underneath, show the seconds as a dot moving along the bottom row
+	if c.matrix.Height >= c.font.height+2 {
+		x := now.Second() * c.matrix.Width / 60
+		if i := c.matrix.Index(x, c.matrix.Height-1); i < pa.NumPixels() {
+			pa.SetOne(i, c.colors.Second)
+		}
+	}
+}
+
+func (c *Clock) Name() string {
+	return "CLOCK"
+}
+
+// Countdown counts down to a target time.  On a matrix it shows the time remaining,
+// and on a strip it shows a bar that shrinks as the target gets closer
+type Countdown struct {
+	target  time.Time
+	color   pixarray.Pixel
+	font    bitmapFont
+	matrix  Matrix
+	reverse bool
+	total   time.Duration
+}
+
+// NewCountdown creates a countdown effect.  Reverse anchors the bar at the end of the strip
+func NewCountdown(target time.Time, color pixarray.Pixel, font bitmapFont, matrix Matrix, reverse bool) *Countdown {
+	cd := Countdown{}
+	cd.target = target
+	cd.color = color
+	cd.font = font
+	cd.matrix = matrix
+	cd.reverse = reverse
+	return &cd
+}
+
+func (cd *Countdown) Start(pa *pixarray.PixArray, now time.Time) {
+	cd.total = cd.target.Sub(now)
+}
+
+func (cd *Countdown) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	remaining := cd.target.Sub(now)
+
+	//	Once we've reached the target, we're done
+	if remaining <= 0 || cd.total <= 0 {
+		pa.SetAll(pixarray.Pixel{})
+		return 0
+	}
+
+	pa.SetAll(pixarray.Pixel{})
+	progress := float64(remaining) / float64(cd.total)
+
+	if !cd.matrix.IsSet() {
+		cd.drawBar(pa, progress, pa.NumPixels(), func(i int) int { return i })
+		return time.Millisecond
+	}
+
+	//	Show the days and hours, the hours and minutes, or the minutes and seconds left
+	var text string
+	switch {
+	case remaining >= 24*time.Hour:
+		text = fmt.Sprintf("%dd%dh", int(remaining.Hours())/24, int(remaining.Hours())%24)
+	case remaining >= time.Hour:
+		text = fmt.Sprintf("%d:%02d", int(remaining.Hours()), int(remaining.Minutes())%60)
+	default:
+		text = fmt.Sprintf("%d:%02d", int(remaining.Minutes()), int(remaining.Seconds())%60)
+	}
+
+	columns := renderText(cd.font, text)
+	drawText(pa, cd.matrix, columns, (cd.matrix.Width-len(columns))/2, (cd.matrix.Height-cd.font.height)/2,
+		func(x int) pixarray.Pixel { return cd.color })
+
+	//	If there's room underneath, show the bar along the bottom row
+	if cd.matrix.Height >= cd.font.height+2 {
+		cd.drawBar(pa, progress, cd.matrix.Width, func(i int) int { return cd.matrix.Index(i, cd.matrix.Height-1) })
+	}
+
+	return time.Millisecond
+}
+
+// drawBar draws a bar that is progress (0 - 1) of the passed length.  The last led
+// fades out, so the bar shrinks smoothly
+func (cd *Countdown) drawBar(pa *pixarray.PixArray, progress float64, length int, index func(i int) int) {
+	lit := progress * float64(length)
+	for i := 0; i < length && float64(i) < lit; i++ {
+		c := cd.color
+		if float64(i+1) > lit {
+			c = Scale(c, float32(frac(lit)))
+		}
+
+		pos := i
+		if cd.reverse {
+			pos = length - 1 - i
+		}
+
+		if p := index(pos); p < pa.NumPixels() {
+			pa.SetOne(p, c)
+		}
+	}
+}
+
+func (cd *Countdown) Name() string {
+	return "COUNTDOWN"
+}
@@ -0,0 +1,35 @@
+package leds
+
+import "testing"
+
+func TestRingIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		pos     float64
+		offset  int
+		reverse bool
+		want    int
+	}{
+		{name: "Top", pos: 0, want: 0},
+		{name: "Half way", pos: 0.5, want: 30},
+		{name: "Past a full turn", pos: 1.25, want: 15},
+		{name: "Negative position", pos: -0.25, want: 45},
+		{name: "Offset", pos: 0.5, offset: 10, want: 40},
+		{name: "Offset wraps", pos: 0.75, offset: 20, want: 5},
+		{name: "Negative offset", pos: 0, offset: -1, want: 59},
+		{name: "Offset past the ring", pos: 0, offset: 61, want: 1},
+		{name: "Negative offset past the ring", pos: 0, offset: -121, want: 59},
+		{name: "Reverse top", pos: 0, reverse: true, want: 0},
+		{name: "Reverse quarter", pos: 0.25, reverse: true, want: 45},
+		{name: "Reverse with offset", pos: 0.25, offset: 20, reverse: true, want: 5},
+		{name: "Reverse with negative offset", pos: 0.75, offset: -20, reverse: true, want: 55},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ringIndex(tt.pos, 60, tt.offset, tt.reverse); got != tt.want {
+				t.Errorf("ringIndex(%v, 60, %v, %v) = %v, want %v", tt.pos, tt.offset, tt.reverse, got, tt.want)
+			}
+		})
+	}
+}
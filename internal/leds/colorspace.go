@@ -0,0 +1,145 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/data"
+	"github.com/danesparza/fxpixel/internal/data/const/effect"
+	"math"
+	"strings"
+)
+
+// blend mixes c1 and c2 by t (0 - 1) in the passed color space.  The white
+// channel is always blended linearly
+func blend(c1, c2 pixarray.Pixel, t float64, colorSpace string) pixarray.Pixel {
+	switch strings.ToLower(colorSpace) {
+	case effect.COLORSPACE_HSV:
+		return blendHSV(c1, c2, t)
+	case effect.COLORSPACE_OKLAB:
+		return blendOKLab(c1, c2, t)
+	}
+
+	return lerp(c1, c2, float32(t))
+}
+
+// blendHSV blends the hue the short way around the color wheel
+func blendHSV(c1, c2 pixarray.Pixel, t float64) pixarray.Pixel {
+	h1, s1, v1 := rgbToHSV(c1)
+	h2, s2, v2 := rgbToHSV(c2)
+
+	//	Greys don't have a meaningful hue, so borrow the other one
+	if s1 == 0 {
+		h1 = h2
+	}
+	if s2 == 0 {
+		h2 = h1
+	}
+
+	dh := h2 - h1
+	if dh > 180 {
+		dh -= 360
+	} else if dh < -180 {
+		dh += 360
+	}
+
+	retval := hsvToRGB(math.Mod(h1+dh*t+360, 360), s1+(s2-s1)*t, v1+(v2-v1)*t)
+	retval.W = c1.W + int(t*float64(c2.W-c1.W))
+	return retval
+}
+
+// blendOKLab blends in the OKLab perceptual color space, which keeps the
+// midpoint of complementary colors from turning muddy
+func blendOKLab(c1, c2 pixarray.Pixel, t float64) pixarray.Pixel {
+	l1, a1, b1 := rgbToOKLab(c1)
+	l2, a2, b2 := rgbToOKLab(c2)
+
+	retval := okLabToRGB(l1+(l2-l1)*t, a1+(a2-a1)*t, b1+(b2-b1)*t)
+	retval.W = c1.W + int(t*float64(c2.W-c1.W))
+	return retval
+}
+
+// rgbToHSV returns hue (0 - 360), saturation and value (0 - 1)
+func rgbToHSV(c pixarray.Pixel) (h, s, v float64) {
+	r := float64(c.R) / 255
+	g := float64(c.G) / 255
+	b := float64(c.B) / 255
+
+	cmax := math.Max(r, math.Max(g, b))
+	cmin := math.Min(r, math.Min(g, b))
+	delta := cmax - cmin
+
+	switch {
+	case delta == 0:
+		h = 0
+	case cmax == r:
+		h = 60 * math.Mod((g-b)/delta, 6)
+	case cmax == g:
+		h = 60 * ((b-r)/delta + 2)
+	default:
+		h = 60 * ((r-g)/delta + 4)
+	}
+	if h < 0 {
+		h += 360
+	}
+
+	if cmax > 0 {
+		s = delta / cmax
+	}
+
+	return h, s, cmax
+}
+
+// hsvToRGB converts hue (0 - 360), saturation and value (0 - 1) to a pixel
+func hsvToRGB(h, s, v float64) pixarray.Pixel {
+	c := data.HSVColor(h, s, v)
+	return pixarray.Pixel{R: c.R, G: c.G, B: c.B}
+}
+
+// srgbToLinear undoes the sRGB gamma curve for a single channel (0 - 255)
+func srgbToLinear(c int) float64 {
+	f := float64(c) / 255
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB applies the sRGB gamma curve and scales back to 0 - 255
+func linearToSRGB(f float64) int {
+	if f <= 0.0031308 {
+		return clampChannel(f * 12.92 * 255)
+	}
+	return clampChannel((1.055*math.Pow(f, 1/2.4) - 0.055) * 255)
+}
+
+// rgbToOKLab converts a pixel to OKLab (see https://bottosson.github.io/posts/oklab/)
+func rgbToOKLab(c pixarray.Pixel) (l, a, b float64) {
+	r := srgbToLinear(c.R)
+	g := srgbToLinear(c.G)
+	bl := srgbToLinear(c.B)
+
+	lc := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*bl)
+	mc := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*bl)
+	sc := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*bl)
+
+	l = 0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc
+	a = 1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc
+	b = 0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc
+	return l, a, b
+}
+
+// okLabToRGB converts an OKLab color back to a pixel
+func okLabToRGB(l, a, b float64) pixarray.Pixel {
+	lc := l + 0.3963377774*a + 0.2158037573*b
+	mc := l - 0.1055613458*a - 0.0638541728*b
+	sc := l - 0.0894841775*a - 1.2914855480*b
+
+	lc = lc * lc * lc
+	mc = mc * mc * mc
+	sc = sc * sc * sc
+
+	return pixarray.Pixel{
+		R: linearToSRGB(4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc),
+		G: linearToSRGB(-1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc),
+		B: linearToSRGB(-0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc),
+	}
+}
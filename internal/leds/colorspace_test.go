@@ -0,0 +1,97 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/data"
+	"github.com/danesparza/fxpixel/internal/data/const/effect"
+	"testing"
+)
+
+func TestBlend(t *testing.T) {
+	red := pixarray.Pixel{R: 255}
+	green := pixarray.Pixel{G: 255}
+	blue := pixarray.Pixel{B: 255}
+	yellow := pixarray.Pixel{R: 255, G: 255}
+
+	tests := []struct {
+		name       string
+		c1, c2     pixarray.Pixel
+		t          float64
+		colorSpace string
+		want       pixarray.Pixel
+	}{
+		{name: "RGB midpoint", c1: red, c2: green, t: 0.5, colorSpace: effect.COLORSPACE_RGB, want: pixarray.Pixel{R: 128, G: 127}},
+		{name: "Blank is RGB", c1: red, c2: blue, t: 0.5, want: pixarray.Pixel{R: 128, B: 127}},
+		{name: "Unknown is RGB", c1: red, c2: blue, t: 0.5, colorSpace: "cmyk", want: pixarray.Pixel{R: 128, B: 127}},
+		{name: "HSV midpoint", c1: red, c2: green, t: 0.5, colorSpace: effect.COLORSPACE_HSV, want: yellow},
+		{name: "HSV short way around", c1: red, c2: blue, t: 0.5, colorSpace: effect.COLORSPACE_HSV, want: pixarray.Pixel{R: 255, B: 255}},
+		{name: "HSV from black borrows the hue", c1: pixarray.Pixel{}, c2: red, t: 0.5, colorSpace: effect.COLORSPACE_HSV, want: pixarray.Pixel{R: 128, G: 64, B: 64}},
+		{name: "OKLab midpoint", c1: red, c2: green, t: 0.5, colorSpace: effect.COLORSPACE_OKLAB, want: pixarray.Pixel{R: 208, G: 168}},
+		{name: "OKLab complementary midpoint", c1: blue, c2: yellow, t: 0.5, colorSpace: effect.COLORSPACE_OKLAB, want: pixarray.Pixel{R: 108, G: 171, B: 199}},
+		{name: "OKLab any case", c1: red, c2: blue, t: 0.5, colorSpace: "OKLab", want: pixarray.Pixel{R: 140, G: 83, B: 162}},
+		{name: "OKLab start", c1: red, c2: blue, t: 0, colorSpace: effect.COLORSPACE_OKLAB, want: red},
+		{name: "OKLab end", c1: red, c2: blue, t: 1, colorSpace: effect.COLORSPACE_OKLAB, want: blue},
+		{name: "White is linear", c1: pixarray.Pixel{W: 100}, c2: pixarray.Pixel{R: 255, W: 200}, t: 0.5, colorSpace: effect.COLORSPACE_OKLAB, want: pixarray.Pixel{R: 99, W: 150}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := blend(tt.c1, tt.c2, tt.t, tt.colorSpace); got != tt.want {
+				t.Errorf("blend(%+v, %+v, %v, %q) = %+v, want %+v", tt.c1, tt.c2, tt.t, tt.colorSpace, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOKLabRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		color pixarray.Pixel
+	}{
+		{name: "Black", color: pixarray.Pixel{}},
+		{name: "White", color: pixarray.Pixel{R: 255, G: 255, B: 255}},
+		{name: "Red", color: pixarray.Pixel{R: 255}},
+		{name: "Dark", color: pixarray.Pixel{R: 3, G: 1, B: 2}},
+		{name: "Green-ish", color: pixarray.Pixel{R: 12, G: 200, B: 77}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := okLabToRGB(rgbToOKLab(tt.color)); got != tt.color {
+				t.Errorf("okLabToRGB(rgbToOKLab(%+v)) = %+v", tt.color, got)
+			}
+		})
+	}
+}
+
+func TestGradientStops(t *testing.T) {
+	//	Gradient stops blend in the palette's color space
+	stops := []data.ColorStop{
+		{Position: 0, Color: data.MetaColor{R: 255}},
+		{Position: 0.25, Color: data.MetaColor{R: 255}},
+		{Position: 0.75, Color: data.MetaColor{G: 255}},
+	}
+
+	tests := []struct {
+		name       string
+		colorSpace string
+		t          float64
+		want       pixarray.Pixel
+	}{
+		{name: "RGB", colorSpace: effect.COLORSPACE_RGB, t: 0.5, want: pixarray.Pixel{R: 128, G: 127}},
+		{name: "HSV", colorSpace: effect.COLORSPACE_HSV, t: 0.5, want: pixarray.Pixel{R: 255, G: 255}},
+		{name: "OKLab", colorSpace: effect.COLORSPACE_OKLAB, t: 0.5, want: pixarray.Pixel{R: 208, G: 168}},
+		{name: "Holds the first stop", colorSpace: effect.COLORSPACE_OKLAB, t: 0.2, want: pixarray.Pixel{R: 255}},
+		{name: "Wraps back to the first stop", colorSpace: effect.COLORSPACE_RGB, t: 0.875, want: pixarray.Pixel{R: 127, G: 128}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPaletteFromStops(stops, true)
+			p.colorSpace = tt.colorSpace
+			if got := p.colorAt(tt.t); got != tt.want {
+				t.Errorf("colorAt(%v) = %+v, want %+v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
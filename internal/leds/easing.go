@@ -0,0 +1,28 @@
+package leds
+
+import (
+	"github.com/danesparza/fxpixel/internal/ease"
+	"github.com/rs/zerolog/log"
+	"strings"
+)
+
+// easingFunc maps linear progress (0 - 1) onto an easing curve
+type easingFunc = ease.Func
+
+// easing finds the named easing curve.  Blank or unknown names use the fallback curve
+func easing(name, fallback string) easingFunc {
+	if strings.TrimSpace(name) == "" {
+		name = fallback
+	}
+
+	f, err := ease.Parse(name)
+	if err != nil {
+		log.Warn().Err(err).Str("fallback", fallback).Msg("Unknown easing curve")
+		f, _ = ease.Parse(fallback)
+	}
+
+	//	Progress is always clamped before it goes in the curve
+	return func(t float64) float64 {
+		return f(clamp01(t))
+	}
+}
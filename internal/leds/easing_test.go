@@ -0,0 +1,30 @@
+package leds
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEasing(t *testing.T) {
+	tests := []struct {
+		name     string
+		curve    string
+		fallback string
+		t        float64
+		want     float64
+	}{
+		{name: "Named curve", curve: "ease-in-quad", fallback: "linear", t: 0.5, want: 0.25},
+		{name: "Blank uses the fallback", curve: " ", fallback: "ease-out-quad", t: 0.5, want: 0.75},
+		{name: "Unknown uses the fallback", curve: "wobbly", fallback: "ease-in-cubic", t: 0.5, want: 0.125},
+		{name: "Progress before the start", curve: "ease-in-quad", fallback: "linear", t: -1, want: 0},
+		{name: "Progress after the end", curve: "ease-in-quad", fallback: "linear", t: 2, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := easing(tt.curve, tt.fallback)(tt.t); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("easing(%q, %q)(%v) = %v, want %v", tt.curve, tt.fallback, tt.t, got, tt.want)
+			}
+		})
+	}
+}
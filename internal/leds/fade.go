@@ -0,0 +1,128 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"time"
+)
+
+// mix blends from a to b.  T can overshoot 0 - 1 a little (for elastic easing),
+// so the channels are clamped
+func mix(a, b pixarray.Pixel, t float64) pixarray.Pixel {
+	channel := func(x, y int) int {
+		return clampChannel(float64(x) + t*float64(y-x))
+	}
+
+	return pixarray.Pixel{
+		R: channel(a.R, b.R),
+		G: channel(a.G, b.G),
+		B: channel(a.B, b.B),
+		W: channel(a.W, b.W),
+	}
+}
+
+// Fade fades each pixel from a starting color to a target color, along an easing curve.
+// The from and to colors repeat along the strip, so a single color fades every pixel
+type Fade struct {
+	fadeTime time.Duration
+	from     []pixarray.Pixel
+	to       []pixarray.Pixel
+	ease     easingFunc
+	start    time.Time
+}
+
+// NewFade creates a fade to the passed colors.  If from is empty, the fade
+// starts from the pixels as they are
+func NewFade(fadeTime time.Duration, from, to []pixarray.Pixel, ease easingFunc) *Fade {
+	f := Fade{}
+	f.fadeTime = fadeTime
+	f.from = from
+	f.to = to
+	f.ease = ease
+	return &f
+}
+
+func (f *Fade) Start(pa *pixarray.PixArray, now time.Time) {
+	f.start = now
+	if len(f.from) == 0 {
+		f.from = pa.GetPixels()
+	}
+	if len(f.to) == 0 {
+		f.to = []pixarray.Pixel{{}}
+	}
+}
+
+func (f *Fade) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	t := 1.0
+	if f.fadeTime > 0 {
+		t = float64(now.Sub(f.start)) / float64(f.fadeTime)
+	}
+
+	progress := 1.0
+	if t < 1 {
+		progress = f.ease(t)
+	}
+
+	for i := 0; i < pa.NumPixels(); i++ {
+		pa.SetOne(i, mix(f.from[i%len(f.from)], f.to[i%len(f.to)], progress))
+	}
+
+	if t >= 1 {
+		return 0
+	}
+	return time.Millisecond
+}
+
+func (f *Fade) Name() string {
+	return "FADE"
+}
+
+// Zip sweeps a color along the strip, with the head of the sweep following an easing curve
+type Zip struct {
+	zipTime time.Duration
+	dest    pixarray.Pixel
+	ease    easingFunc
+	from    []pixarray.Pixel
+	start   time.Time
+}
+
+// NewZip creates a zip of the passed color
+func NewZip(zipTime time.Duration, dest pixarray.Pixel, ease easingFunc) *Zip {
+	z := Zip{}
+	z.zipTime = zipTime
+	z.dest = dest
+	z.ease = ease
+	return &z
+}
+
+func (z *Zip) Start(pa *pixarray.PixArray, now time.Time) {
+	z.start = now
+	z.from = pa.GetPixels()
+}
+
+func (z *Zip) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	t := 1.0
+	if z.zipTime > 0 {
+		t = float64(now.Sub(z.start)) / float64(z.zipTime)
+	}
+
+	if t >= 1 {
+		pa.SetAll(z.dest)
+		return 0
+	}
+
+	//	Curves like bounce move the head backwards too, so pixels
+	//	ahead of the head get their original color back
+	head := z.ease(t) * float64(len(z.from))
+	for i, p := range z.from {
+		if float64(i) < head {
+			p = z.dest
+		}
+		pa.SetOne(i, p)
+	}
+
+	return time.Millisecond
+}
+
+func (z *Zip) Name() string {
+	return "ZIP"
+}
@@ -0,0 +1,342 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/data"
+	"github.com/danesparza/fxpixel/internal/data/const/step"
+	"github.com/rs/zerolog/log"
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Filter defaults (see data.StepFilter)
+const (
+	defaultFilterBrightness = 0.5
+	defaultFilterBlur       = 1
+	defaultFilterTrails     = 500 // milliseconds
+	defaultFilterPosterize  = 4
+	defaultFilterSparkle    = 20 // sparkles a second
+	defaultFilterTint       = 0.5
+)
+
+// sparkleFade is how long a sparkle takes to fade out
+const sparkleFade = 200 * time.Millisecond
+
+// frameFilter changes a frame after the effect has drawn it
+type frameFilter interface {
+	apply(frame []pixarray.Pixel, now time.Time)
+}
+
+// pixelFilter is a filter that changes each pixel on its own
+type pixelFilter func(p pixarray.Pixel) pixarray.Pixel
+
+func (f pixelFilter) apply(frame []pixarray.Pixel, now time.Time) {
+	for i, p := range frame {
+		frame[i] = f(p)
+	}
+}
+
+// FilterChain runs a list of filters over each frame, in order
+type FilterChain struct {
+	filters  []frameFilter
+	animated bool // Some filters change over time, so the frame keeps changing even if the effect doesn't
+}
+
+// NewFilterChain creates a chain from the passed filters.  The matrix layout (if set) makes
+// blurs run across the matrix.  Returns nil if there aren't any filters
+func NewFilterChain(filters []data.StepFilter, numPixels int, matrix Matrix, rnd *rand.Rand, now time.Time) *FilterChain {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	c := FilterChain{}
+	for _, f := range filters {
+		switch strings.ToLower(f.Type) {
+		case step.FILTER_HUE_SHIFT:
+			c.filters = append(c.filters, &hueShift{degrees: amount(f, 0), speed: f.Speed, start: now})
+			c.animated = c.animated || f.Speed != 0
+
+		case step.FILTER_SATURATION:
+			factor := amount(f, 0)
+			c.filters = append(c.filters, pixelFilter(func(p pixarray.Pixel) pixarray.Pixel {
+				h, s, v := rgbToHSV(p)
+				retval := hsvToRGB(h, math.Min(s*factor, 1), v)
+				retval.W = p.W
+				return retval
+			}))
+
+		case step.FILTER_BRIGHTNESS:
+			factor := amount(f, defaultFilterBrightness)
+			c.filters = append(c.filters, pixelFilter(func(p pixarray.Pixel) pixarray.Pixel {
+				return pixarray.Pixel{
+					R: clampChannel(float64(p.R) * factor),
+					G: clampChannel(float64(p.G) * factor),
+					B: clampChannel(float64(p.B) * factor),
+					W: clampChannel(float64(p.W) * factor),
+				}
+			}))
+
+		case step.FILTER_BLUR:
+			//	There's no point blurring further than the strip (or matrix) is long
+			size := numPixels
+			if matrix.IsSet() {
+				size = int(math.Max(float64(matrix.Width), float64(matrix.Height)))
+			}
+			radius := round(math.Min(math.Min(amount(f, defaultFilterBlur), data.MaxFilterBlur), float64(size)))
+			if radius > 0 {
+				c.filters = append(c.filters, newBlur(radius, numPixels, matrix))
+			}
+
+		case step.FILTER_TRAILS:
+			fade := time.Duration(amount(f, defaultFilterTrails) * float64(time.Millisecond))
+			c.filters = append(c.filters, &trails{fade: fade, last: make([]float64, numPixels*4)})
+			c.animated = true
+
+		case step.FILTER_POSTERIZE:
+			levels := round(amount(f, defaultFilterPosterize))
+			if levels < 2 {
+				levels = 2
+			}
+			size := 255 / float64(levels-1)
+			channel := func(c int) int {
+				return clampChannel(float64(round(float64(c)/size)) * size)
+			}
+			c.filters = append(c.filters, pixelFilter(func(p pixarray.Pixel) pixarray.Pixel {
+				return pixarray.Pixel{R: channel(p.R), G: channel(p.G), B: channel(p.B), W: channel(p.W)}
+			}))
+
+		case step.FILTER_INVERT:
+			c.filters = append(c.filters, pixelFilter(func(p pixarray.Pixel) pixarray.Pixel {
+				return pixarray.Pixel{R: 255 - p.R, G: 255 - p.G, B: 255 - p.B, W: p.W}
+			}))
+
+		case step.FILTER_SPARKLE:
+			color := pixarray.Pixel{R: 255, G: 255, B: 255}
+			if f.Color != nil {
+				color = pixarray.Pixel{R: f.Color.R, G: f.Color.G, B: f.Color.B, W: f.Color.W}
+			}
+			c.filters = append(c.filters, &sparkle{
+				rate:  math.Min(amount(f, defaultFilterSparkle), data.MaxFilterSparkle),
+				color: color,
+				rnd:   rnd,
+				level: make([]float64, numPixels),
+			})
+			c.animated = true
+
+		case step.FILTER_TINT:
+			if f.Color == nil {
+				continue
+			}
+			color := pixarray.Pixel{R: f.Color.R, G: f.Color.G, B: f.Color.B, W: f.Color.W}
+			t := clamp01(amount(f, defaultFilterTint))
+			c.filters = append(c.filters, pixelFilter(func(p pixarray.Pixel) pixarray.Pixel {
+				//	Keep the pixel's brightness, but move it towards the tint color
+				return mix(p, Scale(color, float32(maxP(p))/255), t)
+			}))
+
+		default:
+			log.Warn().Str("type", f.Type).Msg("Unknown filter.  Skipping it")
+		}
+	}
+
+	return &c
+}
+
+// apply runs the filters over the frame
+func (c *FilterChain) apply(frame []pixarray.Pixel, now time.Time) {
+	for _, f := range c.filters {
+		f.apply(frame, now)
+	}
+}
+
+// amount returns the filter's amount, or the passed default if it isn't set
+func amount(f data.StepFilter, defaultAmount float64) float64 {
+	if f.Amount == nil {
+		return defaultAmount
+	}
+	return *f.Amount
+}
+
+// hueShift turns the hue of each pixel, and keeps turning it at speed degrees a second
+type hueShift struct {
+	degrees float64
+	speed   float64
+	start   time.Time
+}
+
+func (h *hueShift) apply(frame []pixarray.Pixel, now time.Time) {
+	shift := math.Mod(h.degrees+h.speed*now.Sub(h.start).Seconds(), 360)
+	if shift < 0 {
+		shift += 360
+	}
+
+	for i, p := range frame {
+		hue, s, v := rgbToHSV(p)
+		retval := hsvToRGB(math.Mod(hue+shift, 360), s, v)
+		retval.W = p.W
+		frame[i] = retval
+	}
+}
+
+// blur averages each pixel with its neighbors
+type blur struct {
+	neighbors [][]int
+	scratch   []pixarray.Pixel
+}
+
+// blurKey identifies a set of blur neighbors
+type blurKey struct {
+	radius    int
+	numPixels int
+	matrix    Matrix
+}
+
+// blurNeighbors keeps the neighbors we've worked out (by blurKey), so each step
+// doesn't have to work them out again
+var blurNeighbors sync.Map
+
+// newBlur works out each pixel's neighbors: the pixels within radius along the strip,
+// or within a square around it on a matrix
+func newBlur(radius, numPixels int, matrix Matrix) *blur {
+	b := blur{
+		neighbors: make([][]int, numPixels),
+		scratch:   make([]pixarray.Pixel, numPixels),
+	}
+
+	key := blurKey{radius: radius, numPixels: numPixels, matrix: matrix}
+	if neighbors, found := blurNeighbors.Load(key); found {
+		b.neighbors = neighbors.([][]int)
+		return &b
+	}
+	defer blurNeighbors.Store(key, b.neighbors)
+
+	for i := range b.neighbors {
+		for n := i - radius; n <= i+radius; n++ {
+			if n >= 0 && n < numPixels {
+				b.neighbors[i] = append(b.neighbors[i], n)
+			}
+		}
+	}
+
+	if matrix.IsSet() {
+		for y := 0; y < matrix.Height; y++ {
+			for x := 0; x < matrix.Width; x++ {
+				i := matrix.Index(x, y)
+				if i >= numPixels {
+					continue
+				}
+
+				b.neighbors[i] = nil
+				for ny := y - radius; ny <= y+radius; ny++ {
+					for nx := x - radius; nx <= x+radius; nx++ {
+						if nx < 0 || ny < 0 || nx >= matrix.Width || ny >= matrix.Height {
+							continue
+						}
+						if n := matrix.Index(nx, ny); n < numPixels {
+							b.neighbors[i] = append(b.neighbors[i], n)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return &b
+}
+
+func (b *blur) apply(frame []pixarray.Pixel, now time.Time) {
+	copy(b.scratch, frame)
+
+	for i, neighbors := range b.neighbors {
+		var r, g, bl, w int
+		for _, n := range neighbors {
+			p := b.scratch[n]
+			r, g, bl, w = r+p.R, g+p.G, bl+p.B, w+p.W
+		}
+
+		count := float64(len(neighbors))
+		frame[i] = pixarray.Pixel{
+			R: clampChannel(float64(r) / count),
+			G: clampChannel(float64(g) / count),
+			B: clampChannel(float64(bl) / count),
+			W: clampChannel(float64(w) / count),
+		}
+	}
+}
+
+// trails leaves a fading trail behind anything that moves.  Each channel fades from
+// full brightness to off over the fade time, unless the effect draws it brighter
+type trails struct {
+	fade time.Duration
+	last []float64 // What we showed last time (4 channels for each pixel)
+	at   time.Time
+}
+
+func (t *trails) apply(frame []pixarray.Pixel, now time.Time) {
+	decay := 255.0
+	if !t.at.IsZero() && t.fade > 0 {
+		decay = 255 * float64(now.Sub(t.at)) / float64(t.fade)
+	}
+	t.at = now
+
+	channel := func(c int, last *float64) int {
+		*last = math.Max(float64(c), *last-decay)
+		return clampChannel(*last)
+	}
+
+	for i, p := range frame {
+		last := t.last[i*4 : i*4+4]
+		frame[i] = pixarray.Pixel{
+			R: channel(p.R, &last[0]),
+			G: channel(p.G, &last[1]),
+			B: channel(p.B, &last[2]),
+			W: channel(p.W, &last[3]),
+		}
+	}
+}
+
+// sparkle lights random pixels in the sparkle color, which then fade back out
+type sparkle struct {
+	rate  float64 // Sparkles a second
+	color pixarray.Pixel
+	rnd   *rand.Rand
+	level []float64 // How much of the sparkle color each pixel shows (0 - 1)
+	at    time.Time
+}
+
+func (s *sparkle) apply(frame []pixarray.Pixel, now time.Time) {
+	if len(s.level) == 0 {
+		return
+	}
+
+	elapsed := time.Duration(0)
+	if !s.at.IsZero() {
+		elapsed = now.Sub(s.at)
+	}
+	s.at = now
+
+	//	Fade the sparkles we have ...
+	fade := float64(elapsed) / float64(sparkleFade)
+	for i := range s.level {
+		s.level[i] = math.Max(s.level[i]-fade, 0)
+	}
+
+	//	... and start new ones.  There's no point starting more than there are pixels
+	expected := math.Min(s.rate*elapsed.Seconds(), float64(len(s.level)))
+	count := int(expected)
+	if s.rnd.Float64() < expected-float64(count) {
+		count++
+	}
+	for n := 0; n < count; n++ {
+		s.level[s.rnd.Intn(len(s.level))] = 1
+	}
+
+	for i, level := range s.level {
+		if level > 0 {
+			frame[i] = mix(frame[i], s.color, level)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package leds
+
+import (
+	"github.com/danesparza/fxpixel/internal/data/const/effect"
+	"strings"
+)
+
+// bitmapFont is a fixed size font covering printable ascii (' ' to '~')
+type bitmapFont struct {
+	width       int
+	height      int
+	columnMajor bool     // Glyphs are stored one byte per column (bit 0 at the top), otherwise one byte per row (bit 0 on the left)
+	glyphs      [][]byte // Glyphs, starting with ' '
+}
+
+// on returns true if the pixel at x/y of the glyph for r is lit.  Characters
+// the font doesn't have are shown as '?'
+func (f bitmapFont) on(r rune, x, y int) bool {
+	if r < ' ' || int(r-' ') >= len(f.glyphs) {
+		r = '?'
+	}
+	glyph := f.glyphs[r-' ']
+
+	if f.columnMajor {
+		return glyph[x]&(1<<y) != 0
+	}
+	return glyph[y]&(1<<x) != 0
+}
+
+// findFont returns the built-in font with the passed name.  Defaults to 5x7
+func findFont(name string) bitmapFont {
+	if strings.ToLower(name) == effect.FONT_8X8 {
+		return font8x8
+	}
+	return font5x7
+}
+
+// font5x7 is the classic 5x7 character LCD font
+var font5x7 = bitmapFont{
+	width:       5,
+	height:      7,
+	columnMajor: true,
+	glyphs: [][]byte{
+		{0x00, 0x00, 0x00, 0x00, 0x00}, // ' '
+		{0x00, 0x00, 0x5F, 0x00, 0x00}, // '!'
+		{0x00, 0x07, 0x00, 0x07, 0x00}, // '"'
+		{0x14, 0x7F, 0x14, 0x7F, 0x14}, // '#'
+		{0x24, 0x2A, 0x7F, 0x2A, 0x12}, // '$'
+		{0x23, 0x13, 0x08, 0x64, 0x62}, // '%'
+		{0x36, 0x49, 0x55, 0x22, 0x50}, // '&'
+		{0x00, 0x05, 0x03, 0x00, 0x00}, // '''
+		{0x00, 0x1C, 0x22, 0x41, 0x00}, // '('
+		{0x00, 0x41, 0x22, 0x1C, 0x00}, // ')'
+		{0x08, 0x2A, 0x1C, 0x2A, 0x08}, // '*'
+		{0x08, 0x08, 0x3E, 0x08, 0x08}, // '+'
+		{0x00, 0x50, 0x30, 0x00, 0x00}, // ','
+		{0x08, 0x08, 0x08, 0x08, 0x08}, // '-'
+		{0x00, 0x60, 0x60, 0x00, 0x00}, // '.'
+		{0x20, 0x10, 0x08, 0x04, 0x02}, // '/'
+		{0x3E, 0x51, 0x49, 0x45, 0x3E}, // '0'
+		{0x00, 0x42, 0x7F, 0x40, 0x00}, // '1'
+		{0x42, 0x61, 0x51, 0x49, 0x46}, // '2'
+		{0x21, 0x41, 0x45, 0x4B, 0x31}, // '3'
+		{0x18, 0x14, 0x12, 0x7F, 0x10}, // '4'
+		{0x27, 0x45, 0x45, 0x45, 0x39}, // '5'
+		{0x3C, 0x4A, 0x49, 0x49, 0x30}, // '6'
+		{0x01, 0x71, 0x09, 0x05, 0x03}, // '7'
+		{0x36, 0x49, 0x49, 0x49, 0x36}, // '8'
+		{0x06, 0x49, 0x49, 0x29, 0x1E}, // '9'
+		{0x00, 0x36, 0x36, 0x00, 0x00}, // ':'
+		{0x00, 0x56, 0x36, 0x00, 0x00}, // ';'
+		{0x08, 0x14, 0x22, 0x41, 0x00}, // '<'
+		{0x14, 0x14, 0x14, 0x14, 0x14}, // '='
+		{0x00, 0x41, 0x22, 0x14, 0x08}, // '>'
+		{0x02, 0x01, 0x51, 0x09, 0x06}, // '?'
+		{0x32, 0x49, 0x79, 0x41, 0x3E}, // '@'
+		{0x7E, 0x11, 0x11, 0x11, 0x7E}, // 'A'
+		{0x7F, 0x49, 0x49, 0x49, 0x36}, // 'B'
+		{0x3E, 0x41, 0x41, 0x41, 0x22}, // 'C'
+		{0x7F, 0x41, 0x41, 0x22, 0x1C}, // 'D'
+		{0x7F, 0x49, 0x49, 0x49, 0x41}, // 'E'
+		{0x7F, 0x09, 0x09, 0x01, 0x01}, // 'F'
+		{0x3E, 0x41, 0x41, 0x51, 0x32}, // 'G'
+		{0x7F, 0x08, 0x08, 0x08, 0x7F}, // 'H'
+		{0x00, 0x41, 0x7F, 0x41, 0x00}, // 'I'
+		{0x20, 0x40, 0x41, 0x3F, 0x01}, // 'J'
+		{0x7F, 0x08, 0x14, 0x22, 0x41}, // 'K'
+		{0x7F, 0x40, 0x40, 0x40, 0x40}, // 'L'
+		{0x7F, 0x02, 0x04, 0x02, 0x7F}, // 'M'
+		{0x7F, 0x04, 0x08, 0x10, 0x7F}, // 'N'
+		{0x3E, 0x41, 0x41, 0x41, 0x3E}, // 'O'
+		{0x7F, 0x09, 0x09, 0x09, 0x06}, // 'P'
+		{0x3E, 0x41, 0x51, 0x21, 0x5E}, // 'Q'
+		{0x7F, 0x09, 0x19, 0x29, 0x46}, // 'R'
+		{0x46, 0x49, 0x49, 0x49, 0x31}, // 'S'
+		{0x01, 0x01, 0x7F, 0x01, 0x01}, // 'T'
+		{0x3F, 0x40, 0x40, 0x40, 0x3F}, // 'U'
+		{0x1F, 0x20, 0x40, 0x20, 0x1F}, // 'V'
+		{0x7F, 0x20, 0x18, 0x20, 0x7F}, // 'W'
+		{0x63, 0x14, 0x08, 0x14, 0x63}, // 'X'
+		{0x03, 0x04, 0x78, 0x04, 0x03}, // 'Y'
+		{0x61, 0x51, 0x49, 0x45, 0x43}, // 'Z'
+		{0x00, 0x7F, 0x41, 0x41, 0x00}, // '['
+		{0x02, 0x04, 0x08, 0x10, 0x20}, // '\'
+		{0x00, 0x41, 0x41, 0x7F, 0x00}, // ']'
+		{0x04, 0x02, 0x01, 0x02, 0x04}, // '^'
+		{0x40, 0x40, 0x40, 0x40, 0x40}, // '_'
+		{0x00, 0x01, 0x02, 0x04, 0x00}, // '`'
+		{0x20, 0x54, 0x54, 0x54, 0x78}, // 'a'
+		{0x7F, 0x48, 0x44, 0x44, 0x38}, // 'b'
+		{0x38, 0x44, 0x44, 0x44, 0x20}, // 'c'
+		{0x38, 0x44, 0x44, 0x48, 0x7F}, // 'd'
+		{0x38, 0x54, 0x54, 0x54, 0x18}, // 'e'
+		{0x08, 0x7E, 0x09, 0x01, 0x02}, // 'f'
+		{0x0C, 0x52, 0x52, 0x52, 0x3E}, // 'g'
+		{0x7F, 0x08, 0x04, 0x04, 0x78}, // 'h'
+		{0x00, 0x44, 0x7D, 0x40, 0x00}, // 'i'
+		{0x20, 0x40, 0x44, 0x3D, 0x00}, // 'j'
+		{0x7F, 0x10, 0x28, 0x44, 0x00}, // 'k'
+		{0x00, 0x41, 0x7F, 0x40, 0x00}, // 'l'
+		{0x7C, 0x04, 0x18, 0x04, 0x78}, // 'm'
+		{0x7C, 0x08, 0x04, 0x04, 0x78}, // 'n'
+		{0x38, 0x44, 0x44, 0x44, 0x38}, // 'o'
+		{0x7C, 0x14, 0x14, 0x14, 0x08}, // 'p'
+		{0x08, 0x14, 0x14, 0x18, 0x7C}, // 'q'
+		{0x7C, 0x08, 0x04, 0x04, 0x08}, // 'r'
+		{0x48, 0x54, 0x54, 0x54, 0x20}, // 's'
+		{0x04, 0x3F, 0x44, 0x40, 0x20}, // 't'
+		{0x3C, 0x40, 0x40, 0x20, 0x7C}, // 'u'
+		{0x1C, 0x20, 0x40, 0x20, 0x1C}, // 'v'
+		{0x3C, 0x40, 0x30, 0x40, 0x3C}, // 'w'
+		{0x44, 0x28, 0x10, 0x28, 0x44}, // 'x'
+		{0x0C, 0x50, 0x50, 0x50, 0x3C}, // 'y'
+		{0x44, 0x64, 0x54, 0x4C, 0x44}, // 'z'
+		{0x00, 0x08, 0x36, 0x41, 0x00}, // '{'
+		{0x00, 0x00, 0x7F, 0x00, 0x00}, // '|'
+		{0x00, 0x41, 0x36, 0x08, 0x00}, // '}'
+		{0x08, 0x04, 0x08, 0x10, 0x08}, // '~'
+	},
+}
+
+// font8x8 is a public domain 8x8 font, based on the IBM PC BIOS font
+var font8x8 = bitmapFont{
+	width:  8,
+	height: 8,
+	glyphs: [][]byte{
+		{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // ' '
+		{0x18, 0x3C, 0x3C, 0x18, 0x18, 0x00, 0x18, 0x00}, // '!'
+		{0x36, 0x36, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // '"'
+		{0x36, 0x36, 0x7F, 0x36, 0x7F, 0x36, 0x36, 0x00}, // '#'
+		{0x0C, 0x3E, 0x03, 0x1E, 0x30, 0x1F, 0x0C, 0x00}, // '$'
+		{0x00, 0x63, 0x33, 0x18, 0x0C, 0x66, 0x63, 0x00}, // '%'
+		{0x1C, 0x36, 0x1C, 0x6E, 0x3B, 0x33, 0x6E, 0x00}, // '&'
+		{0x06, 0x06, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00}, // '''
+		{0x18, 0x0C, 0x06, 0x06, 0x06, 0x0C, 0x18, 0x00}, // '('
+		{0x06, 0x0C, 0x18, 0x18, 0x18, 0x0C, 0x06, 0x00}, // ')'
+		{0x00, 0x66, 0x3C, 0xFF, 0x3C, 0x66, 0x00, 0x00}, // '*'
+		{0x00, 0x0C, 0x0C, 0x3F, 0x0C, 0x0C, 0x00, 0x00}, // '+'
+		{0x00, 0x00, 0x00, 0x00, 0x00, 0x0C, 0x0C, 0x06}, // ','
+		{0x00, 0x00, 0x00, 0x3F, 0x00, 0x00, 0x00, 0x00}, // '-'
+		{0x00, 0x00, 0x00, 0x00, 0x00, 0x0C, 0x0C, 0x00}, // '.'
+		{0x60, 0x30, 0x18, 0x0C, 0x06, 0x03, 0x01, 0x00}, // '/'
+		{0x3E, 0x63, 0x73, 0x7B, 0x6F, 0x67, 0x3E, 0x00}, // '0'
+		{0x0C, 0x0E, 0x0C, 0x0C, 0x0C, 0x0C, 0x3F, 0x00}, // '1'
+		{0x1E, 0x33, 0x30, 0x1C, 0x06, 0x33, 0x3F, 0x00}, // '2'
+		{0x1E, 0x33, 0x30, 0x1C, 0x30, 0x33, 0x1E, 0x00}, // '3'
+		{0x38, 0x3C, 0x36, 0x33, 0x7F, 0x30, 0x78, 0x00}, // '4'
+		{0x3F, 0x03, 0x1F, 0x30, 0x30, 0x33, 0x1E, 0x00}, // '5'
+		{0x1C, 0x06, 0x03, 0x1F, 0x33, 0x33, 0x1E, 0x00}, // '6'
+		{0x3F, 0x33, 0x30, 0x18, 0x0C, 0x0C, 0x0C, 0x00}, // '7'
+		{0x1E, 0x33, 0x33, 0x1E, 0x33, 0x33, 0x1E, 0x00}, // '8'
+		{0x1E, 0x33, 0x33, 0x3E, 0x30, 0x18, 0x0E, 0x00}, // '9'
+		{0x00, 0x0C, 0x0C, 0x00, 0x00, 0x0C, 0x0C, 0x00}, // ':'
+		{0x00, 0x0C, 0x0C, 0x00, 0x00, 0x0C, 0x0C, 0x06}, // ';'
+		{0x18, 0x0C, 0x06, 0x03, 0x06, 0x0C, 0x18, 0x00}, // '<'
+		{0x00, 0x00, 0x3F, 0x00, 0x00, 0x3F, 0x00, 0x00}, // '='
+		{0x06, 0x0C, 0x18, 0x30, 0x18, 0x0C, 0x06, 0x00}, // '>'
+		{0x1E, 0x33, 0x30, 0x18, 0x0C, 0x00, 0x0C, 0x00}, // '?'
+		{0x3E, 0x63, 0x7B, 0x7B, 0x7B, 0x03, 0x1E, 0x00}, // '@'
+		{0x0C, 0x1E, 0x33, 0x33, 0x3F, 0x33, 0x33, 0x00}, // 'A'
+		{0x3F, 0x66, 0x66, 0x3E, 0x66, 0x66, 0x3F, 0x00}, // 'B'
+		{0x3C, 0x66, 0x03, 0x03, 0x03, 0x66, 0x3C, 0x00}, // 'C'
+		{0x1F, 0x36, 0x66, 0x66, 0x66, 0x36, 0x1F, 0x00}, // 'D'
+		{0x7F, 0x46, 0x16, 0x1E, 0x16, 0x46, 0x7F, 0x00}, // 'E'
+		{0x7F, 0x46, 0x16, 0x1E, 0x16, 0x06, 0x0F, 0x00}, // 'F'
+		{0x3C, 0x66, 0x03, 0x03, 0x73, 0x66, 0x7C, 0x00}, // 'G'
+		{0x33, 0x33, 0x33, 0x3F, 0x33, 0x33, 0x33, 0x00}, // 'H'
+		{0x1E, 0x0C, 0x0C, 0x0C, 0x0C, 0x0C, 0x1E, 0x00}, // 'I'
+		{0x78, 0x30, 0x30, 0x30, 0x33, 0x33, 0x1E, 0x00}, // 'J'
+		{0x67, 0x66, 0x36, 0x1E, 0x36, 0x66, 0x67, 0x00}, // 'K'
+		{0x0F, 0x06, 0x06, 0x06, 0x46, 0x66, 0x7F, 0x00}, // 'L'
+		{0x63, 0x77, 0x7F, 0x7F, 0x6B, 0x63, 0x63, 0x00}, // 'M'
+		{0x63, 0x67, 0x6F, 0x7B, 0x73, 0x63, 0x63, 0x00}, // 'N'
+		{0x1C, 0x36, 0x63, 0x63, 0x63, 0x36, 0x1C, 0x00}, // 'O'
+		{0x3F, 0x66, 0x66, 0x3E, 0x06, 0x06, 0x0F, 0x00}, // 'P'
+		{0x1E, 0x33, 0x33, 0x33, 0x3B, 0x1E, 0x38, 0x00}, // 'Q'
+		{0x3F, 0x66, 0x66, 0x3E, 0x36, 0x66, 0x67, 0x00}, // 'R'
+		{0x1E, 0x33, 0x07, 0x0E, 0x38, 0x33, 0x1E, 0x00}, // 'S'
+		{0x3F, 0x2D, 0x0C, 0x0C, 0x0C, 0x0C, 0x1E, 0x00}, // 'T'
+		{0x33, 0x33, 0x33, 0x33, 0x33, 0x33, 0x3F, 0x00}, // 'U'
+		{0x33, 0x33, 0x33, 0x33, 0x33, 0x1E, 0x0C, 0x00}, // 'V'
+		{0x63, 0x63, 0x63, 0x6B, 0x7F, 0x77, 0x63, 0x00}, // 'W'
+		{0x63, 0x63, 0x36, 0x1C, 0x1C, 0x36, 0x63, 0x00}, // 'X'
+		{0x33, 0x33, 0x33, 0x1E, 0x0C, 0x0C, 0x1E, 0x00}, // 'Y'
+		{0x7F, 0x63, 0x31, 0x18, 0x4C, 0x66, 0x7F, 0x00}, // 'Z'
+		{0x1E, 0x06, 0x06, 0x06, 0x06, 0x06, 0x1E, 0x00}, // '['
+		{0x03, 0x06, 0x0C, 0x18, 0x30, 0x60, 0x40, 0x00}, // '\'
+		{0x1E, 0x18, 0x18, 0x18, 0x18, 0x18, 0x1E, 0x00}, // ']'
+		{0x08, 0x1C, 0x36, 0x63, 0x00, 0x00, 0x00, 0x00}, // '^'
+		{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF}, // '_'
+		{0x0C, 0x0C, 0x18, 0x00, 0x00, 0x00, 0x00, 0x00}, // '`'
+		{0x00, 0x00, 0x1E, 0x30, 0x3E, 0x33, 0x6E, 0x00}, // 'a'
+		{0x07, 0x06, 0x06, 0x3E, 0x66, 0x66, 0x3B, 0x00}, // 'b'
+		{0x00, 0x00, 0x1E, 0x33, 0x03, 0x33, 0x1E, 0x00}, // 'c'
+		{0x38, 0x30, 0x30, 0x3E, 0x33, 0x33, 0x6E, 0x00}, // 'd'
+		{0x00, 0x00, 0x1E, 0x33, 0x3F, 0x03, 0x1E, 0x00}, // 'e'
+		{0x1C, 0x36, 0x06, 0x0F, 0x06, 0x06, 0x0F, 0x00}, // 'f'
+		{0x00, 0x00, 0x6E, 0x33, 0x33, 0x3E, 0x30, 0x1F}, // 'g'
+		{0x07, 0x06, 0x36, 0x6E, 0x66, 0x66, 0x67, 0x00}, // 'h'
+		{0x0C, 0x00, 0x0E, 0x0C, 0x0C, 0x0C, 0x1E, 0x00}, // 'i'
+		{0x30, 0x00, 0x30, 0x30, 0x30, 0x33, 0x33, 0x1E}, // 'j'
+		{0x07, 0x06, 0x66, 0x36, 0x1E, 0x36, 0x67, 0x00}, // 'k'
+		{0x0E, 0x0C, 0x0C, 0x0C, 0x0C, 0x0C, 0x1E, 0x00}, // 'l'
+		{0x00, 0x00, 0x33, 0x7F, 0x7F, 0x6B, 0x63, 0x00}, // 'm'
+		{0x00, 0x00, 0x1F, 0x33, 0x33, 0x33, 0x33, 0x00}, // 'n'
+		{0x00, 0x00, 0x1E, 0x33, 0x33, 0x33, 0x1E, 0x00}, // 'o'
+		{0x00, 0x00, 0x3B, 0x66, 0x66, 0x3E, 0x06, 0x0F}, // 'p'
+		{0x00, 0x00, 0x6E, 0x33, 0x33, 0x3E, 0x30, 0x78}, // 'q'
+		{0x00, 0x00, 0x3B, 0x6E, 0x66, 0x06, 0x0F, 0x00}, // 'r'
+		{0x00, 0x00, 0x3E, 0x03, 0x1E, 0x30, 0x1F, 0x00}, // 's'
+		{0x08, 0x0C, 0x3E, 0x0C, 0x0C, 0x2C, 0x18, 0x00}, // 't'
+		{0x00, 0x00, 0x33, 0x33, 0x33, 0x33, 0x6E, 0x00}, // 'u'
+		{0x00, 0x00, 0x33, 0x33, 0x33, 0x1E, 0x0C, 0x00}, // 'v'
+		{0x00, 0x00, 0x63, 0x6B, 0x7F, 0x7F, 0x36, 0x00}, // 'w'
+		{0x00, 0x00, 0x63, 0x36, 0x1C, 0x36, 0x63, 0x00}, // 'x'
+		{0x00, 0x00, 0x33, 0x33, 0x33, 0x3E, 0x30, 0x1F}, // 'y'
+		{0x00, 0x00, 0x3F, 0x19, 0x0C, 0x26, 0x3F, 0x00}, // 'z'
+		{0x38, 0x0C, 0x0C, 0x07, 0x0C, 0x0C, 0x38, 0x00}, // '{'
+		{0x18, 0x18, 0x18, 0x00, 0x18, 0x18, 0x18, 0x00}, // '|'
+		{0x07, 0x0C, 0x0C, 0x38, 0x0C, 0x0C, 0x07, 0x00}, // '}'
+		{0x6E, 0x3B, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // '~'
+	},
+}
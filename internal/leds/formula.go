@@ -0,0 +1,139 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/audio"
+	"github.com/danesparza/fxpixel/internal/data"
+	"github.com/danesparza/fxpixel/internal/formula"
+	"time"
+)
+
+// compileFormula compiles a formula with the inputs and outputs the formula effect provides
+func compileFormula(src string) (*formula.Program, error) {
+	return formula.Compile(src, data.FormulaVariables()...)
+}
+
+// formulaSlots are where the formula keeps each input and output
+type formulaSlots struct {
+	index, x, y, t, count, width, height, level, beat int
+	h, s, v, r, g, b, w                               int
+}
+
+// Formula colors each pixel with a formula, evaluated every frame.  Formulas that set
+// r, g, b or w are in RGB, otherwise they're in HSV.  Everything is 0 - 1
+type Formula struct {
+	program *formula.Program
+	input   audio.Input
+	matrix  Matrix
+	vars    []float64
+	slots   formulaSlots
+	rgb     bool
+	xs      []float64
+	ys      []float64
+	start   time.Time
+}
+
+// NewFormula creates a formula effect.  The audio input is optional
+func NewFormula(program *formula.Program, input audio.Input, matrix Matrix) *Formula {
+	f := Formula{}
+	f.program = program
+	f.input = input
+	f.matrix = matrix
+	f.vars = program.Vars()
+
+	slot := func(name string) int {
+		i, _ := program.Slot(name)
+		return i
+	}
+	f.slots = formulaSlots{
+		index: slot("index"), x: slot("x"), y: slot("y"), t: slot("t"), count: slot("count"),
+		width: slot("width"), height: slot("height"), level: slot("level"), beat: slot("beat"),
+		h: slot("h"), s: slot("s"), v: slot("v"), r: slot("r"), g: slot("g"), b: slot("b"), w: slot("w"),
+	}
+	f.rgb = program.Assigns("r") || program.Assigns("g") || program.Assigns("b") || program.Assigns("w")
+	return &f
+}
+
+func (f *Formula) Start(pa *pixarray.PixArray, now time.Time) {
+	f.start = now
+
+	//	Figure out where each pixel is (0 - 1 in each direction)
+	n := pa.NumPixels()
+	f.xs = make([]float64, n)
+	f.ys = make([]float64, n)
+	for i := 0; i < n; i++ {
+		if n > 1 {
+			f.xs[i] = float64(i) / float64(n-1)
+		}
+	}
+
+	if f.matrix.IsSet() {
+		for y := 0; y < f.matrix.Height; y++ {
+			for x := 0; x < f.matrix.Width; x++ {
+				i := f.matrix.Index(x, y)
+				if i >= n {
+					continue
+				}
+				f.xs[i], f.ys[i] = 0, 0
+				if f.matrix.Width > 1 {
+					f.xs[i] = float64(x) / float64(f.matrix.Width-1)
+				}
+				if f.matrix.Height > 1 {
+					f.ys[i] = float64(y) / float64(f.matrix.Height-1)
+				}
+			}
+		}
+	}
+
+	f.vars[f.slots.count] = float64(n)
+	f.vars[f.slots.width] = float64(n)
+	f.vars[f.slots.height] = 1
+	if f.matrix.IsSet() {
+		f.vars[f.slots.width] = float64(f.matrix.Width)
+		f.vars[f.slots.height] = float64(f.matrix.Height)
+	}
+}
+
+func (f *Formula) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	v := f.vars
+	v[f.slots.t] = now.Sub(f.start).Seconds()
+
+	if f.input != nil {
+		frame := f.input.Latest()
+		v[f.slots.level] = frame.Level
+		v[f.slots.beat] = 0
+		if frame.Beat {
+			v[f.slots.beat] = 1
+		}
+	}
+
+	for i := range f.xs {
+		v[f.slots.index] = float64(i)
+		v[f.slots.x] = f.xs[i]
+		v[f.slots.y] = f.ys[i]
+
+		//	Every pixel starts from the defaults
+		v[f.slots.h], v[f.slots.s], v[f.slots.v] = 0, 1, 1
+		v[f.slots.r], v[f.slots.g], v[f.slots.b], v[f.slots.w] = 0, 0, 0, 0
+
+		f.program.Run(v)
+
+		if f.rgb {
+			pa.SetOne(i, pixarray.Pixel{
+				R: clampChannel(v[f.slots.r] * 255),
+				G: clampChannel(v[f.slots.g] * 255),
+				B: clampChannel(v[f.slots.b] * 255),
+				W: clampChannel(v[f.slots.w] * 255),
+			})
+			continue
+		}
+
+		pa.SetOne(i, hsvPixel(v[f.slots.h], v[f.slots.s], v[f.slots.v]))
+	}
+
+	return time.Millisecond
+}
+
+func (f *Formula) Name() string {
+	return "FORMULA"
+}
@@ -0,0 +1,206 @@
+package leds
+
+import (
+	"fmt"
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/data/const/effect"
+	"image"
+	"image/draw"
+	"image/gif"
+	"os"
+	"strings"
+	"time"
+)
+
+// loadGif decodes all the frames of the animated gif at the passed path
+func loadGif(path string) (*gif.GIF, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("problem opening gif: %v", err)
+	}
+	defer f.Close()
+
+	g, err := gif.DecodeAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("problem decoding gif: %v", err)
+	}
+
+	return g, nil
+}
+
+// gifFrames renders each frame of the gif onto a full size canvas.  Gif frames
+// are often just the part of the picture that changed, so each one is drawn over
+// what came before it (following the frame's disposal method)
+func gifFrames(g *gif.GIF) []image.Image {
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	canvas := image.NewRGBA(bounds)
+
+	retval := []image.Image{}
+	for i, frame := range g.Image {
+		//	Save what's there, in case this frame needs to be undone
+		var previous *image.RGBA
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(bounds)
+			draw.Draw(previous, bounds, canvas, image.Point{}, draw.Src)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		//	Keep a copy of the finished frame
+		rendered := image.NewRGBA(bounds)
+		draw.Draw(rendered, bounds, canvas, image.Point{}, draw.Src)
+		retval = append(retval, rendered)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return retval
+}
+
+// scaleToMatrix scales the image to the matrix.  'fit' shows the whole image (with dark
+// bars if the shapes don't match) and 'fill' crops the image so it covers the whole matrix.
+// The result is indexed by [y][x]
+func scaleToMatrix(img image.Image, m Matrix, scale string) [][]pixarray.Pixel {
+	bounds := img.Bounds()
+	srcW := bounds.Dx()
+	srcH := bounds.Dy()
+
+	//	Fill: crop the source to the same shape as the matrix
+	if strings.ToLower(scale) == effect.GIF_FILL {
+		cropW, cropH := srcW, srcH
+		if srcW*m.Height > srcH*m.Width {
+			cropW = srcH * m.Width / m.Height
+		} else {
+			cropH = srcW * m.Height / m.Width
+		}
+
+		x0 := bounds.Min.X + (srcW-cropW)/2
+		y0 := bounds.Min.Y + (srcH-cropH)/2
+		cropped := image.NewRGBA(image.Rect(0, 0, cropW, cropH))
+		draw.Draw(cropped, cropped.Bounds(), img, image.Pt(x0, y0), draw.Src)
+
+		return scaleImage(cropped, m.Width, m.Height)
+	}
+
+	//	Fit: scale the whole image down to fit inside the matrix and center it
+	fitW, fitH := m.Width, m.Height
+	if srcW*m.Height > srcH*m.Width {
+		fitH = srcH * m.Width / srcW
+	} else {
+		fitW = srcW * m.Height / srcH
+	}
+	if fitW < 1 {
+		fitW = 1
+	}
+	if fitH < 1 {
+		fitH = 1
+	}
+
+	scaled := scaleImage(img, fitW, fitH)
+	offsetX := (m.Width - fitW) / 2
+	offsetY := (m.Height - fitH) / 2
+
+	retval := make([][]pixarray.Pixel, m.Height)
+	for y := range retval {
+		retval[y] = make([]pixarray.Pixel, m.Width)
+		if y-offsetY >= 0 && y-offsetY < fitH {
+			copy(retval[y][offsetX:], scaled[y-offsetY])
+		}
+	}
+
+	return retval
+}
+
+// GifPlayer plays the frames of an animated gif on a matrix
+type GifPlayer struct {
+	gif    *gif.GIF
+	matrix Matrix
+	loops  int
+	scale  string
+	frames [][][]pixarray.Pixel
+	delays []time.Duration
+	total  time.Duration
+	start  time.Time
+}
+
+// NewGifPlayer creates a gif effect.  Loops is the number of times to play the animation
+// (0 means keep looping) and scale is 'fit' or 'fill'
+func NewGifPlayer(g *gif.GIF, matrix Matrix, loops int, scale string) *GifPlayer {
+	gp := GifPlayer{}
+	gp.gif = g
+	gp.matrix = matrix
+	gp.loops = loops
+	gp.scale = scale
+	return &gp
+}
+
+func (gp *GifPlayer) Start(pa *pixarray.PixArray, now time.Time) {
+	gp.start = now
+
+	//	Without a matrix, treat the strip as a single row
+	if !gp.matrix.IsSet() {
+		gp.matrix = Matrix{Width: pa.NumPixels(), Height: 1}
+	}
+
+	//	Scale each frame up front, so playback is just a lookup
+	gp.frames = nil
+	gp.delays = nil
+	gp.total = 0
+	for i, frame := range gifFrames(gp.gif) {
+		gp.frames = append(gp.frames, scaleToMatrix(frame, gp.matrix, gp.scale))
+
+		//	Delays are in 100ths of a second.  Like browsers do, treat
+		//	really short delays as 'not set' and use 100ms
+		delay := 10
+		if i < len(gp.gif.Delay) && gp.gif.Delay[i] > 1 {
+			delay = gp.gif.Delay[i]
+		}
+		gp.delays = append(gp.delays, time.Duration(delay)*10*time.Millisecond)
+		gp.total += gp.delays[i]
+	}
+}
+
+func (gp *GifPlayer) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	if len(gp.frames) == 0 {
+		return 0
+	}
+
+	elapsed := now.Sub(gp.start)
+
+	//	If we've played all our loops, go dark and finish up
+	if gp.loops > 0 && elapsed >= gp.total*time.Duration(gp.loops) {
+		pa.SetAll(pixarray.Pixel{})
+		return 0
+	}
+
+	//	Find the frame we should be showing
+	pos := elapsed % gp.total
+	frame := 0
+	for frame < len(gp.delays)-1 && pos >= gp.delays[frame] {
+		pos -= gp.delays[frame]
+		frame++
+	}
+
+	for y, row := range gp.frames[frame] {
+		for x, p := range row {
+			if i := gp.matrix.Index(x, y); i < pa.NumPixels() {
+				pa.SetOne(i, p)
+			}
+		}
+	}
+
+	return time.Millisecond
+}
+
+func (gp *GifPlayer) Name() string {
+	return "GIF"
+}
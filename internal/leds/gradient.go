@@ -0,0 +1,54 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"time"
+)
+
+// PaletteGradient spreads a palette across the strip, optionally scrolling it along
+type PaletteGradient struct {
+	colors palette
+	speed  float64
+	start  time.Time
+}
+
+// NewPaletteGradient creates a gradient effect.  Speed is in strip lengths per second
+// (negative scrolls backwards).  A scrolling gradient should use a wrapped palette so
+// it joins up smoothly
+func NewPaletteGradient(colors palette, speed float64) *PaletteGradient {
+	g := PaletteGradient{}
+	g.colors = colors
+	g.speed = speed
+	return &g
+}
+
+func (g *PaletteGradient) Start(pa *pixarray.PixArray, now time.Time) {
+	g.start = now
+}
+
+func (g *PaletteGradient) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	n := pa.NumPixels()
+
+	//	A static gradient runs from the first to the last pixel
+	if g.speed == 0 {
+		for i := 0; i < n; i++ {
+			t := 0.0
+			if n > 1 {
+				t = float64(i) / float64(n-1)
+			}
+			pa.SetOne(i, g.colors.colorAt(t))
+		}
+		return time.Millisecond
+	}
+
+	offset := now.Sub(g.start).Seconds() * g.speed
+	for i := 0; i < n; i++ {
+		pa.SetOne(i, g.colors.colorAt(frac(float64(i)/float64(n)-offset)))
+	}
+
+	return time.Millisecond
+}
+
+func (g *PaletteGradient) Name() string {
+	return "GRADIENT"
+}
@@ -0,0 +1,173 @@
+package leds
+
+import (
+	"fmt"
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"image"
+	_ "image/gif"  // Register the gif decoder
+	_ "image/jpeg" // Register the jpeg decoder
+	_ "image/png"  // Register the png decoder
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// findImage returns the path to an image in the image store, looked up by id or name
+func (sp StepProcessor) findImage(name string) (string, error) {
+	storedImage, found := sp.Images[name]
+	if !found {
+		storedImage, found = sp.Images[strings.ToLower(name)]
+	}
+	if !found {
+		return "", fmt.Errorf("image not found: %v", name)
+	}
+
+	return filepath.Join(sp.ImageDir, storedImage.FileName), nil
+}
+
+// loadImage decodes the image at the passed path.  For animated images, this is the first frame
+func loadImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("problem opening image: %v", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("problem decoding image: %v", err)
+	}
+
+	return img, nil
+}
+
+// scaleImage resizes the image to the passed width and height, averaging the source pixels
+// that fall in each target pixel.  The result is indexed by [y][x]
+func scaleImage(img image.Image, width, height int) [][]pixarray.Pixel {
+	bounds := img.Bounds()
+	srcW := bounds.Dx()
+	srcH := bounds.Dy()
+
+	retval := make([][]pixarray.Pixel, height)
+	for y := 0; y < height; y++ {
+		retval[y] = make([]pixarray.Pixel, width)
+
+		//	Find the source rows for this target row (at least one)
+		y0 := y * srcH / height
+		y1 := (y + 1) * srcH / height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := (x + 1) * srcW / width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, count uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					//	Colors are 16 bit and premultiplied by alpha, so transparent areas come out dark
+					cr, cg, cb, _ := img.At(bounds.Min.X+sx, bounds.Min.Y+sy).RGBA()
+					r += cr >> 8
+					g += cg >> 8
+					b += cb >> 8
+					count++
+				}
+			}
+
+			retval[y][x] = pixarray.Pixel{R: int(r / count), G: int(g / count), B: int(b / count)}
+		}
+	}
+
+	return retval
+}
+
+// ImagePlayer plays an image a column at a time along the strip (for light painting and
+// persistence of vision displays).  On a matrix, the image scrolls through a row at a time
+type ImagePlayer struct {
+	img     image.Image
+	matrix  Matrix
+	rate    float64
+	loops   int
+	reverse bool
+	frame   [][]pixarray.Pixel
+	start   time.Time
+}
+
+// NewImagePlayer creates an image effect.  Rate is the number of columns (or rows on a matrix)
+// to show per second, and loops is the number of times to play the image (0 means keep looping)
+func NewImagePlayer(img image.Image, matrix Matrix, rate float64, loops int, reverse bool) *ImagePlayer {
+	ip := ImagePlayer{}
+	ip.img = img
+	ip.matrix = matrix
+	ip.rate = rate
+	ip.loops = loops
+	ip.reverse = reverse
+	return &ip
+}
+
+func (ip *ImagePlayer) Start(pa *pixarray.PixArray, now time.Time) {
+	ip.start = now
+
+	bounds := ip.img.Bounds()
+
+	//	On a matrix, fit the image to the matrix width and keep the aspect ratio.
+	//	On a strip, each column of the image is stretched to the length of the strip
+	if ip.matrix.IsSet() {
+		height := bounds.Dy() * ip.matrix.Width / bounds.Dx()
+		if height < 1 {
+			height = 1
+		}
+		ip.frame = scaleImage(ip.img, ip.matrix.Width, height)
+	} else {
+		ip.frame = scaleImage(ip.img, bounds.Dx(), pa.NumPixels())
+	}
+}
+
+func (ip *ImagePlayer) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	//	Figure out how many slices (columns or rows) there are in one pass
+	slices := len(ip.frame[0])
+	if ip.matrix.IsSet() {
+		slices = len(ip.frame)
+	}
+
+	pos := int(now.Sub(ip.start).Seconds() * ip.rate)
+
+	//	If we've played all our loops, go dark and finish up
+	if ip.loops > 0 && pos >= slices*ip.loops {
+		pa.SetAll(pixarray.Pixel{})
+		return 0
+	}
+
+	slice := pos % slices
+	if ip.reverse {
+		slice = slices - 1 - slice
+	}
+
+	if ip.matrix.IsSet() {
+		//	Show a window of the image, starting at the current row
+		for y := 0; y < ip.matrix.Height; y++ {
+			row := ip.frame[(slice+y)%slices]
+			for x := 0; x < ip.matrix.Width; x++ {
+				if i := ip.matrix.Index(x, y); i < pa.NumPixels() {
+					pa.SetOne(i, row[x])
+				}
+			}
+		}
+		return time.Millisecond
+	}
+
+	for i := 0; i < pa.NumPixels(); i++ {
+		pa.SetOne(i, ip.frame[i][slice])
+	}
+
+	return time.Millisecond
+}
+
+func (ip *ImagePlayer) Name() string {
+	return "IMAGE"
+}
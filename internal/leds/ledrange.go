@@ -0,0 +1,77 @@
+package leds
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseLedRange converts a range of leds (like "0-74" or "0-9,20,30-39") into a list of
+// led positions.  Positions outside the strip are dropped.  A blank range means the entire strip
+func parseLedRange(ledRange string, numPixels int) []int {
+	retval := []int{}
+
+	if strings.TrimSpace(ledRange) == "" {
+		for i := 0; i < numPixels; i++ {
+			retval = append(retval, i)
+		}
+		return retval
+	}
+
+	for _, part := range strings.Split(ledRange, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		//	Split the start and end (if we have one)
+		start, end, isRange := strings.Cut(part, "-")
+
+		first, err := strconv.Atoi(strings.TrimSpace(start))
+		if err != nil {
+			continue
+		}
+
+		last := first
+		if isRange {
+			//	An open ended range goes to the end of the strip
+			last = numPixels - 1
+			if strings.TrimSpace(end) != "" {
+				last, err = strconv.Atoi(strings.TrimSpace(end))
+				if err != nil {
+					continue
+				}
+			}
+		}
+
+		for i := first; i <= last; i++ {
+			if i >= 0 && i < numPixels {
+				retval = append(retval, i)
+			}
+		}
+	}
+
+	return retval
+}
+
+// ledRangeString is the opposite of parseLedRange.  It turns a list of led positions
+// back into a range (like "0-9,20,30-39")
+func ledRangeString(pixels []int) string {
+	parts := []string{}
+	for i := 0; i < len(pixels); {
+		//	Find the end of this run of positions
+		j := i
+		for j+1 < len(pixels) && pixels[j+1] == pixels[j]+1 {
+			j++
+		}
+
+		if j == i {
+			parts = append(parts, strconv.Itoa(pixels[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", pixels[i], pixels[j]))
+		}
+		i = j + 1
+	}
+
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,60 @@
+package leds
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLedRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		ledRange string
+		want     []int
+	}{
+		{name: "Blank is the whole strip", ledRange: " ", want: []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{name: "Single led", ledRange: "4", want: []int{4}},
+		{name: "Range", ledRange: "2-5", want: []int{2, 3, 4, 5}},
+		{name: "Mixed", ledRange: "0-1, 4 ,7-8", want: []int{0, 1, 4, 7, 8}},
+		{name: "Open ended", ledRange: "7-", want: []int{7, 8, 9}},
+		{name: "Past the end", ledRange: "8-20", want: []int{8, 9}},
+		{name: "Outside the strip", ledRange: "12,-1", want: []int{}},
+		{name: "Backwards range", ledRange: "5-2", want: []int{}},
+		{name: "Not a number", ledRange: "a,3,b-4,2-c", want: []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLedRange(tt.ledRange, 10); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLedRange(%q) = %v, want %v", tt.ledRange, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLedRangeString(t *testing.T) {
+	tests := []struct {
+		name   string
+		pixels []int
+		want   string
+	}{
+		{name: "Empty", pixels: []int{}, want: ""},
+		{name: "Single led", pixels: []int{4}, want: "4"},
+		{name: "Run", pixels: []int{2, 3, 4, 5}, want: "2-5"},
+		{name: "Mixed", pixels: []int{0, 1, 4, 7, 8}, want: "0-1,4,7-8"},
+		{name: "Unordered", pixels: []int{5, 6, 2, 3}, want: "5-6,2-3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ledRangeString(tt.pixels)
+			if got != tt.want {
+				t.Fatalf("ledRangeString(%v) = %q, want %q", tt.pixels, got, tt.want)
+			}
+
+			//	The string should parse back to the same leds
+			if back := parseLedRange(got, 10); got != "" && !reflect.DeepEqual(back, tt.pixels) {
+				t.Errorf("parseLedRange(%q) = %v, want %v", got, back, tt.pixels)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"math/rand"
+	"time"
+)
+
+// lightningBurst is a single flash in a lightning strike
+type lightningBurst struct {
+	start      time.Duration // When the flash starts (from the start of the strike)
+	length     time.Duration // How long the flash shows
+	brightness int           // Brightness of the flash (0 - 255)
+}
+
+// Lightning flashes a strike made up of a few bursts of light.  A strike can light the
+// whole strip, or just a section of it (with the edges falling off)
+type Lightning struct {
+	count      int
+	fixed      bool
+	spacing    time.Duration
+	length     time.Duration
+	brightness int
+	strikeSize int
+	falloff    int
+	rnd        *rand.Rand
+	bursts     []lightningBurst
+	weights    []float32
+	start      time.Time
+}
+
+// NewLightning creates a lightning effect.  With fixed bursts, the strike has exactly count
+// bursts of the passed length, spacing and brightness (0 - 255).  Otherwise it has up to count
+// bursts, and each burst picks its length, spacing and brightness up to those maximums.
+// If strikeSize is set, each strike lights a random section of that many pixels, fading
+// out over falloff pixels at each edge
+func NewLightning(count int, fixed bool, spacing, length time.Duration, brightness, strikeSize, falloff int, rnd *rand.Rand) *Lightning {
+	l := Lightning{}
+	l.count = count
+	l.fixed = fixed
+	l.spacing = spacing
+	l.length = length
+	l.brightness = brightness
+	l.strikeSize = strikeSize
+	l.falloff = falloff
+	l.rnd = rnd
+	return &l
+}
+
+func (l *Lightning) Start(pa *pixarray.PixArray, now time.Time) {
+	l.start = now
+
+	//	Plan the bursts
+	count := l.count
+	if !l.fixed {
+		count = 1 + l.rnd.Intn(l.count)
+	}
+
+	l.bursts = []lightningBurst{}
+	at := time.Duration(0)
+	for b := 0; b < count; b++ {
+		burst := lightningBurst{start: at, length: l.length, brightness: l.brightness}
+		spacing := l.spacing
+		if !l.fixed {
+			burst.length = time.Duration(l.upTo(int64(l.length)))
+			burst.brightness = int(l.upTo(int64(l.brightness)))
+			spacing = time.Duration(l.upTo(int64(l.spacing)))
+		}
+
+		l.bursts = append(l.bursts, burst)
+		at += burst.length + spacing
+	}
+
+	//	Work out which pixels the strike lights (and how brightly)
+	n := pa.NumPixels()
+	l.weights = make([]float32, n)
+	first, size := 0, n
+	if l.strikeSize > 0 && l.strikeSize < n {
+		size = l.strikeSize
+		first = l.rnd.Intn(n - size + 1)
+	}
+
+	for i := 0; i < size; i++ {
+		//	Distance (in pixels) from the nearest edge of the strike
+		edge := i + 1
+		if size-i < edge {
+			edge = size - i
+		}
+
+		w := float32(1)
+		if edge <= l.falloff {
+			w = float32(edge) / float32(l.falloff+1)
+		}
+		l.weights[first+i] = w
+	}
+}
+
+func (l *Lightning) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	elapsed := now.Sub(l.start)
+
+	for _, burst := range l.bursts {
+		if elapsed < burst.start {
+			break
+		}
+
+		if elapsed < burst.start+burst.length {
+			flash := pixarray.Pixel{
+				B: (burst.brightness + 1) / 2,
+				W: burst.brightness,
+			}
+			for i, w := range l.weights {
+				pa.SetOne(i, Scale(flash, w))
+			}
+			return time.Millisecond
+		}
+	}
+
+	//	Dark between bursts (and once we're done)
+	pa.SetAll(pixarray.Pixel{})
+
+	last := l.bursts[len(l.bursts)-1]
+	if elapsed >= last.start+last.length {
+		return 0
+	}
+	return time.Millisecond
+}
+
+func (l *Lightning) Name() string {
+	return "LIGHTNING"
+}
+
+// upTo picks a random value between a quarter of limit and limit.  It works in int64,
+// so durations don't overflow on 32 bit systems
+func (l *Lightning) upTo(limit int64) int64 {
+	if limit <= 0 {
+		return 0
+	}
+	lowest := limit / 4
+	return lowest + l.rnd.Int63n(limit-lowest+1)
+}
@@ -0,0 +1,135 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestLightningBursts(t *testing.T) {
+	tests := []struct {
+		name       string
+		count      int
+		fixed      bool
+		spacing    time.Duration
+		length     time.Duration
+		brightness int
+	}{
+		{name: "Fixed", count: 3, fixed: true, spacing: 80 * time.Millisecond, length: 40 * time.Millisecond, brightness: 200},
+		{name: "Fixed single burst", count: 1, fixed: true, spacing: 80 * time.Millisecond, length: 40 * time.Millisecond, brightness: 255},
+		{name: "Random", count: 5, spacing: 80 * time.Millisecond, length: 40 * time.Millisecond, brightness: 200},
+		{name: "Random without spacing", count: 4, length: 30 * time.Millisecond, brightness: 100},
+		{name: "Random long strike", count: 3, spacing: time.Minute, length: time.Minute, brightness: 255},
+	}
+
+	pa := pixarray.NewPixArray(10, 3, nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 50; seed++ {
+				l := NewLightning(tt.count, tt.fixed, tt.spacing, tt.length, tt.brightness, 0, 0, rand.New(rand.NewSource(seed)))
+				l.Start(pa, time.Now())
+
+				if tt.fixed && len(l.bursts) != tt.count {
+					t.Fatalf("seed %v: %v bursts, want %v", seed, len(l.bursts), tt.count)
+				}
+				if len(l.bursts) < 1 || len(l.bursts) > tt.count {
+					t.Fatalf("seed %v: %v bursts, want 1 - %v", seed, len(l.bursts), tt.count)
+				}
+
+				at := time.Duration(0)
+				for i, b := range l.bursts {
+					if tt.fixed {
+						want := lightningBurst{start: time.Duration(i) * (tt.length + tt.spacing), length: tt.length, brightness: tt.brightness}
+						if b != want {
+							t.Fatalf("seed %v: burst %v = %+v, want %+v", seed, i, b, want)
+						}
+						continue
+					}
+
+					//	Random bursts pick between a quarter of the limit and the limit
+					if b.length < tt.length/4 || b.length > tt.length {
+						t.Errorf("seed %v: burst %v length = %v, want %v - %v", seed, i, b.length, tt.length/4, tt.length)
+					}
+					if b.brightness < tt.brightness/4 || b.brightness > tt.brightness {
+						t.Errorf("seed %v: burst %v brightness = %v, want %v - %v", seed, i, b.brightness, tt.brightness/4, tt.brightness)
+					}
+
+					//	The first burst starts straight away, and each one after that waits for the spacing
+					if gap := b.start - at; i == 0 && gap != 0 {
+						t.Errorf("seed %v: first burst starts at %v, want 0", seed, b.start)
+					} else if i > 0 && (gap < tt.spacing/4 || gap > tt.spacing) {
+						t.Errorf("seed %v: burst %v gap = %v, want %v - %v", seed, i, gap, tt.spacing/4, tt.spacing)
+					}
+					at = b.start + b.length
+				}
+			}
+		})
+	}
+}
+
+func TestLightningSameSeed(t *testing.T) {
+	pa := pixarray.NewPixArray(30, 3, nil)
+
+	plan := func() []lightningBurst {
+		l := NewLightning(6, false, 100*time.Millisecond, 50*time.Millisecond, 255, 8, 2, rand.New(rand.NewSource(42)))
+		l.Start(pa, time.Now())
+		return l.bursts
+	}
+
+	first, second := plan(), plan()
+	if len(first) != len(second) {
+		t.Fatalf("same seed planned %v and %v bursts", len(first), len(second))
+	}
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("same seed burst %v = %+v and %+v", i, first[i], second[i])
+		}
+	}
+}
+
+func TestLightningWeights(t *testing.T) {
+	tests := []struct {
+		name       string
+		strikeSize int
+		falloff    int
+		want       []float32 // Weights across the lit section of the strip
+	}{
+		{name: "Whole strip", want: []float32{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+		{name: "Whole strip with falloff", falloff: 2, want: []float32{1.0 / 3, 2.0 / 3, 1, 1, 1, 1, 1, 1, 2.0 / 3, 1.0 / 3}},
+		{name: "Strike bigger than the strip", strikeSize: 20, want: []float32{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+		{name: "Section", strikeSize: 4, want: []float32{1, 1, 1, 1}},
+		{name: "Section with falloff", strikeSize: 6, falloff: 2, want: []float32{1.0 / 3, 2.0 / 3, 1, 1, 2.0 / 3, 1.0 / 3}},
+	}
+
+	pa := pixarray.NewPixArray(10, 3, nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(0); seed < 20; seed++ {
+				l := NewLightning(1, true, 0, time.Millisecond, 255, tt.strikeSize, tt.falloff, rand.New(rand.NewSource(seed)))
+				l.Start(pa, time.Now())
+
+				//	Find the lit section
+				first := 0
+				for first < len(l.weights) && l.weights[first] == 0 {
+					first++
+				}
+				if first+len(tt.want) > len(l.weights) {
+					t.Fatalf("seed %v: weights = %v, want a section of %v", seed, l.weights, tt.want)
+				}
+
+				for i, w := range l.weights {
+					want := float32(0)
+					if i >= first && i < first+len(tt.want) {
+						want = tt.want[i-first]
+					}
+					if w != want {
+						t.Fatalf("seed %v: weights = %v, want %v starting at %v", seed, l.weights, tt.want, first)
+					}
+				}
+			}
+		})
+	}
+}
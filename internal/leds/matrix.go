@@ -0,0 +1,35 @@
+package leds
+
+import "github.com/danesparza/fxpixel/internal/data"
+
+// Matrix describes how the LEDs are wired when they're laid out as a 2D grid.
+// LED 0 is the top left corner, and rows run left to right
+type Matrix struct {
+	Width      int  // Number of LEDs in each row
+	Height     int  // Number of rows
+	Serpentine bool // Every other row runs right to left (the usual zig-zag wiring)
+}
+
+// MatrixFromConfig reads the matrix layout from the system config (the matrix_width,
+// matrix_height and matrix_serpentine settings).  A width or height of 0 means the LEDs
+// are a plain strip
+func MatrixFromConfig(config data.SystemConfig) Matrix {
+	return Matrix{
+		Width:      config.MatrixWidth,
+		Height:     config.MatrixHeight,
+		Serpentine: config.MatrixSerpentine,
+	}
+}
+
+// IsSet returns true if a matrix layout has been configured
+func (m Matrix) IsSet() bool {
+	return m.Width > 0 && m.Height > 0
+}
+
+// Index returns the LED index for the passed x/y position
+func (m Matrix) Index(x, y int) int {
+	if m.Serpentine && y%2 == 1 {
+		x = m.Width - 1 - x
+	}
+	return y*m.Width + x
+}
@@ -0,0 +1,264 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Skewing and unskewing factors for 2 and 3 dimensional simplex noise
+var (
+	simplexF2 = 0.5 * (math.Sqrt(3.0) - 1.0)
+	simplexG2 = (3.0 - math.Sqrt(3.0)) / 6.0
+	simplexF3 = 1.0 / 3.0
+	simplexG3 = 1.0 / 6.0
+)
+
+var simplexGrad = [12][2]float64{
+	{1, 1}, {-1, 1}, {1, -1}, {-1, -1},
+	{1, 0}, {-1, 0}, {1, 0}, {-1, 0},
+	{0, 1}, {0, -1}, {0, 1}, {0, -1},
+}
+
+var simplexGrad3 = [12][3]float64{
+	{1, 1, 0}, {-1, 1, 0}, {1, -1, 0}, {-1, -1, 0},
+	{1, 0, 1}, {-1, 0, 1}, {1, 0, -1}, {-1, 0, -1},
+	{0, 1, 1}, {0, -1, 1}, {0, 1, -1}, {0, -1, -1},
+}
+
+// simplexPerm is the doubled permutation table used to pick gradients.  It
+// uses a fixed seed so the same meta always produces the same motion
+var simplexPerm = func() [512]int {
+	perm := [512]int{}
+	p := rand.New(rand.NewSource(1)).Perm(256)
+	for i := 0; i < 512; i++ {
+		perm[i] = p[i&255]
+	}
+	return perm
+}()
+
+// simplex2 returns 2 dimensional simplex noise for the given coordinates, in the range -1 to 1
+func simplex2(xin, yin float64) float64 {
+	//	Skew the input space to find the simplex cell we're in
+	s := (xin + yin) * simplexF2
+	i := int(math.Floor(xin + s))
+	j := int(math.Floor(yin + s))
+	t := float64(i+j) * simplexG2
+
+	//	The x,y distances from the cell origin
+	x0 := xin - (float64(i) - t)
+	y0 := yin - (float64(j) - t)
+
+	//	Figure out which of the two triangles in the cell we're in
+	i1, j1 := 0, 1
+	if x0 > y0 {
+		i1, j1 = 1, 0
+	}
+
+	x1 := x0 - float64(i1) + simplexG2
+	y1 := y0 - float64(j1) + simplexG2
+	x2 := x0 - 1.0 + 2.0*simplexG2
+	y2 := y0 - 1.0 + 2.0*simplexG2
+
+	ii := i & 255
+	jj := j & 255
+
+	//	Add up the contributions from each of the three corners
+	n := simplexCorner(x0, y0, simplexPerm[ii+simplexPerm[jj]]%12) +
+		simplexCorner(x1, y1, simplexPerm[ii+i1+simplexPerm[jj+j1]]%12) +
+		simplexCorner(x2, y2, simplexPerm[ii+1+simplexPerm[jj+1]]%12)
+
+	//	Scale the result to stay (roughly) in the range -1 to 1
+	return 70.0 * n
+}
+
+// simplexCorner returns the contribution of a single simplex corner
+func simplexCorner(x, y float64, gi int) float64 {
+	t := 0.5 - x*x - y*y
+	if t < 0 {
+		return 0
+	}
+	t *= t
+	return t * t * (simplexGrad[gi][0]*x + simplexGrad[gi][1]*y)
+}
+
+// simplex3 returns 3 dimensional simplex noise for the given coordinates, in the range -1 to 1
+func simplex3(xin, yin, zin float64) float64 {
+	//	Skew the input space to find the simplex cell we're in
+	s := (xin + yin + zin) * simplexF3
+	i := int(math.Floor(xin + s))
+	j := int(math.Floor(yin + s))
+	k := int(math.Floor(zin + s))
+	t := float64(i+j+k) * simplexG3
+
+	//	The x,y,z distances from the cell origin
+	x0 := xin - (float64(i) - t)
+	y0 := yin - (float64(j) - t)
+	z0 := zin - (float64(k) - t)
+
+	//	Figure out which of the six tetrahedrons in the cell we're in
+	var i1, j1, k1, i2, j2, k2 int
+	switch {
+	case x0 >= y0 && y0 >= z0:
+		i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 1, 0
+	case x0 >= y0 && x0 >= z0:
+		i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 0, 1
+	case x0 >= y0:
+		i1, j1, k1, i2, j2, k2 = 0, 0, 1, 1, 0, 1
+	case y0 < z0:
+		i1, j1, k1, i2, j2, k2 = 0, 0, 1, 0, 1, 1
+	case x0 < z0:
+		i1, j1, k1, i2, j2, k2 = 0, 1, 0, 0, 1, 1
+	default:
+		i1, j1, k1, i2, j2, k2 = 0, 1, 0, 1, 1, 0
+	}
+
+	x1, y1, z1 := x0-float64(i1)+simplexG3, y0-float64(j1)+simplexG3, z0-float64(k1)+simplexG3
+	x2, y2, z2 := x0-float64(i2)+2*simplexG3, y0-float64(j2)+2*simplexG3, z0-float64(k2)+2*simplexG3
+	x3, y3, z3 := x0-1+3*simplexG3, y0-1+3*simplexG3, z0-1+3*simplexG3
+
+	ii := i & 255
+	jj := j & 255
+	kk := k & 255
+
+	//	Add up the contributions from each of the four corners
+	n := simplexCorner3(x0, y0, z0, simplexPerm[ii+simplexPerm[jj+simplexPerm[kk]]]%12) +
+		simplexCorner3(x1, y1, z1, simplexPerm[ii+i1+simplexPerm[jj+j1+simplexPerm[kk+k1]]]%12) +
+		simplexCorner3(x2, y2, z2, simplexPerm[ii+i2+simplexPerm[jj+j2+simplexPerm[kk+k2]]]%12) +
+		simplexCorner3(x3, y3, z3, simplexPerm[ii+1+simplexPerm[jj+1+simplexPerm[kk+1]]]%12)
+
+	//	Scale the result to stay (roughly) in the range -1 to 1
+	return 32.0 * n
+}
+
+// simplexCorner3 returns the contribution of a single 3D simplex corner
+func simplexCorner3(x, y, z float64, gi int) float64 {
+	t := 0.6 - x*x - y*y - z*z
+	if t < 0 {
+		return 0
+	}
+	t *= t
+	return t * t * (simplexGrad3[gi][0]*x + simplexGrad3[gi][1]*y + simplexGrad3[gi][2]*z)
+}
+
+// fbm layers octaves of 2D simplex noise on top of each other (fractal brownian motion)
+func fbm(x, y float64, octaves int) float64 {
+	return fractal(octaves, func(frequency float64) float64 {
+		return simplex2(x*frequency, y*frequency)
+	})
+}
+
+// fbm3 layers octaves of 3D simplex noise on top of each other
+func fbm3(x, y, z float64, octaves int) float64 {
+	return fractal(octaves, func(frequency float64) float64 {
+		return simplex3(x*frequency, y*frequency, z*frequency)
+	})
+}
+
+// fractal adds up octaves of noise.  Each octave is twice the frequency and half the
+// amplitude of the one before it.  There's always at least one octave
+func fractal(octaves int, noise func(frequency float64) float64) float64 {
+	if octaves < 1 {
+		octaves = 1
+	}
+
+	total := 0.0
+	amplitude := 1.0
+	frequency := 1.0
+	sum := 0.0
+	for o := 0; o < octaves; o++ {
+		total += noise(frequency) * amplitude
+		sum += amplitude
+		amplitude /= 2
+		frequency *= 2
+	}
+	return total / sum
+}
+
+// Noise maps a moving fractal noise field onto a palette, giving smooth
+// organic motion that doesn't repeat.  When plasma is set, the field is
+// warped by a second noise field and the palette is cycled through.  On a
+// matrix, the field is 2D (and moves through a third dimension over time)
+type Noise struct {
+	scale   float64
+	speed   float64
+	octaves int
+	colors  palette
+	matrix  Matrix
+	plasma  bool
+	start   time.Time
+}
+
+// NewNoise creates a 'lava lamp' style noise effect.  The matrix layout (if set)
+// makes the noise 2D
+func NewNoise(scale, speed float64, octaves int, colors palette, matrix Matrix) *Noise {
+	n := Noise{}
+	n.scale = scale
+	n.speed = speed
+	n.octaves = octaves
+	n.colors = colors
+	n.matrix = matrix
+	return &n
+}
+
+// NewPlasma creates a plasma effect
+func NewPlasma(scale, speed float64, octaves int, colors palette, matrix Matrix) *Noise {
+	n := NewNoise(scale, speed, octaves, colors, matrix)
+	n.plasma = true
+	return n
+}
+
+func (n *Noise) Start(pa *pixarray.PixArray, now time.Time) {
+	n.start = now
+}
+
+func (n *Noise) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	t := now.Sub(n.start).Seconds() * n.speed
+
+	if n.matrix.IsSet() {
+		for y := 0; y < n.matrix.Height; y++ {
+			for x := 0; x < n.matrix.Width; x++ {
+				if i := n.matrix.Index(x, y); i < pa.NumPixels() {
+					pa.SetOne(i, n.colorAt(float64(x)*n.scale, float64(y)*n.scale, t, true))
+				}
+			}
+		}
+		return time.Millisecond
+	}
+
+	for i := 0; i < pa.NumPixels(); i++ {
+		pa.SetOne(i, n.colorAt(float64(i)*n.scale, 0, t, false))
+	}
+
+	return time.Millisecond
+}
+
+// colorAt works out the color at x (and y, on a matrix) at time t
+func (n *Noise) colorAt(x, y, t float64, matrix bool) pixarray.Pixel {
+	field := func(x, y, t float64) float64 {
+		if matrix {
+			return fbm3(x, y, t, n.octaves)
+		}
+		return fbm(x, t, n.octaves)
+	}
+
+	if n.plasma {
+		//	Warp the position with another noise field, then wrap
+		//	the result around the palette
+		warp := field(x+5.2, y+1.3, t*0.7+1.3)
+		v := field(x+warp, y+warp, t)
+		return n.colors.colorAt(frac(v + t*0.1))
+	}
+
+	//	Stretch the noise a bit, since fractal noise rarely reaches its limits
+	v := 0.5 + field(x, y, t)*0.8
+	return n.colors.colorAt(v)
+}
+
+func (n *Noise) Name() string {
+	if n.plasma {
+		return "PLASMA"
+	}
+	return "NOISE"
+}
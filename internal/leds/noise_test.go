@@ -0,0 +1,66 @@
+package leds
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFbmBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		octaves int
+		matrix  bool
+	}{
+		{name: "No octaves", octaves: 0},
+		{name: "Negative octaves", octaves: -3},
+		{name: "One octave", octaves: 1},
+		{name: "Default octaves", octaves: 3},
+		{name: "Most octaves", octaves: 8},
+		{name: "Matrix no octaves", octaves: 0, matrix: true},
+		{name: "Matrix default octaves", octaves: 3, matrix: true},
+		{name: "Matrix most octaves", octaves: 8, matrix: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 2000; i++ {
+				x := float64(i) * 0.173
+				y := float64(i%37) * 0.291
+				z := float64(i%11) * 0.457
+
+				var got float64
+				if tt.matrix {
+					got = fbm3(x, y, z, tt.octaves)
+				} else {
+					got = fbm(x, y, tt.octaves)
+				}
+
+				if math.IsNaN(got) || got < -1 || got > 1 {
+					t.Fatalf("noise at (%v, %v, %v) = %v, want -1 - 1", x, y, z, got)
+				}
+			}
+		})
+	}
+}
+
+func TestFbmMissingOctaves(t *testing.T) {
+	//	No octaves (or negative octaves) is the same as a single octave
+	tests := []struct {
+		name    string
+		octaves int
+	}{
+		{name: "No octaves", octaves: 0},
+		{name: "Negative octaves", octaves: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := fbm(1.3, 2.7, tt.octaves), fbm(1.3, 2.7, 1); got != want {
+				t.Errorf("fbm(%v octaves) = %v, want %v", tt.octaves, got, want)
+			}
+			if got, want := fbm3(1.3, 2.7, 0.4, tt.octaves), fbm3(1.3, 2.7, 0.4, 1); got != want {
+				t.Errorf("fbm3(%v octaves) = %v, want %v", tt.octaves, got, want)
+			}
+		})
+	}
+}
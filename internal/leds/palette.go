@@ -0,0 +1,149 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/data"
+	"github.com/rs/zerolog/log"
+	"math"
+	"sort"
+	"strings"
+)
+
+// colorStop is a single color at a position (0 - 1) in a palette
+type colorStop struct {
+	position float64
+	color    pixarray.Pixel
+}
+
+// palette is a set of color stops that can be sampled anywhere between 0 and 1
+type palette struct {
+	stops      []colorStop
+	colorSpace string // The color space to blend stops in.  Defaults to rgb
+}
+
+// rainbowColors is the default palette for effects that cycle through colors
+var rainbowColors = []data.MetaColor{
+	{R: 255},
+	{R: 255, G: 255},
+	{G: 255},
+	{G: 255, B: 255},
+	{B: 255},
+	{R: 255, B: 255},
+}
+
+// lavaColors is the default palette for effects that simulate molten motion
+var lavaColors = []data.MetaColor{
+	{},
+	{R: 128},
+	{R: 255, G: 40},
+	{R: 255, G: 120},
+	{R: 255, G: 200, B: 40},
+}
+
+// newPalette spaces the passed colors evenly from 0 to 1.  If wrap is set, the
+// last color blends back into the first so the palette can be cycled smoothly
+func newPalette(colors []data.MetaColor, wrap bool) palette {
+	retval := palette{}
+
+	if len(colors) == 0 {
+		return retval
+	}
+
+	//	Figure out how many gaps we have between colors
+	gaps := len(colors) - 1
+	if wrap {
+		gaps = len(colors)
+	}
+
+	for i, c := range colors {
+		pos := 0.0
+		if gaps > 0 {
+			pos = float64(i) / float64(gaps)
+		}
+
+		retval.stops = append(retval.stops, colorStop{
+			position: pos,
+			color:    pixarray.Pixel{R: c.R, G: c.G, B: c.B, W: c.W},
+		})
+	}
+
+	//	Close the loop back to the first color
+	if wrap {
+		retval.stops = append(retval.stops, colorStop{position: 1, color: retval.stops[0].color})
+	}
+
+	return retval
+}
+
+// newPaletteFromStops creates a palette from positioned color stops.  If wrap is set and
+// the stops don't reach the end, the last color blends back into the first
+func newPaletteFromStops(stops []data.ColorStop, wrap bool) palette {
+	retval := palette{}
+
+	for _, s := range stops {
+		retval.stops = append(retval.stops, colorStop{
+			position: s.Position,
+			color:    pixarray.Pixel{R: s.Color.R, G: s.Color.G, B: s.Color.B, W: s.Color.W},
+		})
+	}
+
+	//	Make sure the stops are in order
+	sort.SliceStable(retval.stops, func(i, j int) bool {
+		return retval.stops[i].position < retval.stops[j].position
+	})
+
+	//	Close the loop back to the first color
+	last := len(retval.stops) - 1
+	if wrap && last >= 0 && retval.stops[last].position < 1 {
+		retval.stops = append(retval.stops, colorStop{position: 1, color: retval.stops[0].color})
+	}
+
+	return retval
+}
+
+// findPalette finds the palette an effect should use.  A stored palette with the passed name
+// wins, then the passed colors, then the default colors
+func (sp StepProcessor) findPalette(name string, colors, defaults []data.MetaColor, wrap bool) palette {
+	if name != "" {
+		if p, found := sp.Palettes[strings.ToLower(name)]; found && len(p.Stops) > 0 {
+			return newPaletteFromStops(sp.useWhiteChannel(p.Stops).([]data.ColorStop), wrap)
+		}
+
+		log.Warn().Str("palette", name).Msg("Palette not found.  Using default colors")
+	}
+
+	if len(colors) == 0 {
+		colors = defaults
+	}
+
+	return newPalette(colors, wrap)
+}
+
+// colorAt returns the palette color at position t (0 - 1), blending between
+// the stops on either side of it
+func (p palette) colorAt(t float64) pixarray.Pixel {
+	if len(p.stops) == 0 {
+		return pixarray.Pixel{}
+	}
+
+	if t <= p.stops[0].position {
+		return p.stops[0].color
+	}
+
+	for i := 1; i < len(p.stops); i++ {
+		if t <= p.stops[i].position {
+			span := p.stops[i].position - p.stops[i-1].position
+			if span <= 0 {
+				return p.stops[i].color
+			}
+			return blend(p.stops[i-1].color, p.stops[i].color, (t-p.stops[i-1].position)/span, p.colorSpace)
+		}
+	}
+
+	return p.stops[len(p.stops)-1].color
+}
+
+// frac returns the fractional part of f, always in the range 0 - 1
+func frac(f float64) float64 {
+	return f - math.Floor(f)
+}
@@ -0,0 +1,98 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/data"
+	"testing"
+)
+
+func TestPaletteColorAt(t *testing.T) {
+	red := data.MetaColor{R: 255}
+	green := data.MetaColor{G: 255}
+	blue := data.MetaColor{B: 255}
+
+	//	Stops are sorted by position, so the order they're passed in shouldn't matter
+	stops := newPaletteFromStops([]data.ColorStop{
+		{Position: 1, Color: green},
+		{Position: 0, Color: red},
+		{Position: 0.5, Color: blue},
+	}, false)
+
+	wrapped := newPaletteFromStops([]data.ColorStop{
+		{Position: 0, Color: red},
+		{Position: 0.5, Color: blue},
+	}, true)
+
+	hardEdge := newPaletteFromStops([]data.ColorStop{
+		{Position: 0, Color: red},
+		{Position: 0.5, Color: red},
+		{Position: 0.5, Color: green},
+		{Position: 1, Color: green},
+	}, false)
+
+	tests := []struct {
+		name    string
+		palette palette
+		t       float64
+		want    pixarray.Pixel
+	}{
+		{name: "Empty palette", palette: palette{}, t: 0.5, want: pixarray.Pixel{}},
+		{name: "Before the first stop", palette: stops, t: -0.2, want: pixarray.Pixel{R: 255}},
+		{name: "First stop", palette: stops, t: 0, want: pixarray.Pixel{R: 255}},
+		{name: "Between stops", palette: stops, t: 0.25, want: pixarray.Pixel{R: 128, B: 127}},
+		{name: "Middle stop", palette: stops, t: 0.5, want: pixarray.Pixel{B: 255}},
+		{name: "Last stop", palette: stops, t: 1, want: pixarray.Pixel{G: 255}},
+		{name: "After the last stop", palette: stops, t: 1.5, want: pixarray.Pixel{G: 255}},
+		{name: "Wrapped back to the first color", palette: wrapped, t: 0.75, want: pixarray.Pixel{R: 127, B: 128}},
+		{name: "Wrapped end", palette: wrapped, t: 1, want: pixarray.Pixel{R: 255}},
+		{name: "Hard edge before", palette: hardEdge, t: 0.49, want: pixarray.Pixel{R: 255}},
+		{name: "Hard edge after", palette: hardEdge, t: 0.51, want: pixarray.Pixel{G: 255}},
+		{name: "Evenly spaced colors", palette: newPalette([]data.MetaColor{red, green, blue}, false), t: 0.5, want: pixarray.Pixel{G: 255}},
+		{name: "Evenly spaced wrapped colors", palette: newPalette([]data.MetaColor{red, green, blue}, true), t: 2.0 / 3, want: pixarray.Pixel{B: 255}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.palette.colorAt(tt.t); got != tt.want {
+				t.Errorf("colorAt(%v) = %+v, want %+v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindPalette(t *testing.T) {
+	sp := StepProcessor{
+		Palettes: map[string]data.Palette{
+			"sunset": {Name: "Sunset", Stops: []data.ColorStop{
+				{Position: 0, Color: data.MetaColor{R: 255, G: 100}},
+				{Position: 1, Color: data.MetaColor{R: 80, B: 120}},
+			}},
+			"empty": {Name: "Empty"},
+		},
+	}
+
+	colors := []data.MetaColor{{G: 255}}
+	defaults := []data.MetaColor{{B: 255}}
+
+	tests := []struct {
+		name   string
+		lookup string
+		colors []data.MetaColor
+		want   pixarray.Pixel
+	}{
+		{name: "Stored palette", lookup: "sunset", colors: colors, want: pixarray.Pixel{R: 255, G: 100}},
+		{name: "Stored palette any case", lookup: "SunSet", colors: colors, want: pixarray.Pixel{R: 255, G: 100}},
+		{name: "Missing palette uses colors", lookup: "nope", colors: colors, want: pixarray.Pixel{G: 255}},
+		{name: "Palette without stops uses colors", lookup: "empty", colors: colors, want: pixarray.Pixel{G: 255}},
+		{name: "No palette uses colors", colors: colors, want: pixarray.Pixel{G: 255}},
+		{name: "Nothing uses defaults", want: pixarray.Pixel{B: 255}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sp.findPalette(tt.lookup, tt.colors, defaults, false).colorAt(0); got != tt.want {
+				t.Errorf("findPalette(%q) first color = %+v, want %+v", tt.lookup, got, tt.want)
+			}
+		})
+	}
+}
@@ -5,11 +5,13 @@ import (
 	"database/sql"
 	"fmt"
 	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/audio"
 	"github.com/danesparza/fxpixel/internal/data"
 	"github.com/danesparza/fxpixel/internal/data/const/effect"
 	stepType "github.com/danesparza/fxpixel/internal/data/const/step"
 	"github.com/rs/zerolog/log"
 	"math/rand"
+	"strings"
 	"sync"
 	"time"
 )
@@ -24,13 +26,40 @@ type timelineProcessMap struct {
 	rwMutex sync.RWMutex
 }
 
+type frameMemory struct {
+	pixels []pixarray.Pixel
+	mutex  sync.Mutex
+}
+
+func (f *frameMemory) get() []pixarray.Pixel {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.pixels
+}
+
+func (f *frameMemory) set(pixels []pixarray.Pixel) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.pixels = pixels
+}
+
 // StepProcessor encapsulates the core config for processing a step
 type StepProcessor struct {
-	GPIO           int
-	LEDs           int
-	PixelOrder     string
-	NumberOfColors int
-	PixArray       *pixarray.PixArray
+	GPIO               int
+	LEDs               int
+	PixelOrder         string
+	NumberOfColors     int
+	PixArray           *pixarray.PixArray
+	MaxStrobeFrequency float64
+	Palettes           map[string]data.Palette
+	Matrix             Matrix
+	ImageDir           string
+	Images             map[string]data.Image
+	Audio              audio.Input
+	Frame              *TransitionStrip
+	ScriptDir          string
+	ScriptFrameLimit   time.Duration
+	Rand               *rand.Rand // Rand is seeded once for each play
 }
 
 // BackgroundProcess encapsulates background processing operations
@@ -47,8 +76,26 @@ type BackgroundProcess struct {
 	// StopAllTimelines signals all running timlines should be stopped
 	StopAllTimelines chan bool
 
+	// MaxStrobeFrequency is the system wide safety cap (in Hz) for strobe effects.  0 means no cap
+	MaxStrobeFrequency float64
+
+	// ImageDir is the directory uploaded images are stored in
+	ImageDir string
+
+	// Audio is the audio input for sound reactive effects.  Nil if there isn't one
+	Audio audio.Input
+
+	// ScriptDir is the directory scripts are stored in
+	ScriptDir string
+
+	// ScriptFrameLimit is the longest a script can take to render a frame
+	ScriptFrameLimit time.Duration
+
 	// PlayingTimelines tracks currently playing timelines
 	PlayingTimelines timelineProcessMap
+
+	// lastFrame is what the LEDs were showing when the last timeline finished (or was stopped)
+	lastFrame frameMemory
 }
 
 // HandleAndProcess handles system context calls and channel events to play/stop timelines
@@ -179,6 +226,27 @@ func (bp *BackgroundProcess) StartTimelinePlay(cx context.Context, req PlayTimel
 		return
 	}
 
+	//	Get the stored palettes, so effects can refer to them by name
+	palettes := map[string]data.Palette{}
+	storedPalettes, err := bp.DB.GetAllPalettes(ctx)
+	if err != nil {
+		log.Err(err).Msg("An error occurred trying to get the stored palettes")
+	}
+	for _, p := range storedPalettes {
+		palettes[strings.ToLower(p.Name)] = p
+	}
+
+	//	Get the stored images, so effects can refer to them by name or id
+	images := map[string]data.Image{}
+	storedImages, err := bp.DB.GetAllImages(ctx)
+	if err != nil {
+		log.Err(err).Msg("An error occurred trying to get the stored images")
+	}
+	for _, i := range storedImages {
+		images[i.ID] = i
+		images[strings.ToLower(i.Name)] = i
+	}
+
 	//	Spin up a strip:
 	pixels, err := NewStrip( // Take the defaults for most things ...
 		systemConfig.LEDs,                               // Set the number of LEDs
@@ -191,18 +259,41 @@ func (bp *BackgroundProcess) StartTimelinePlay(cx context.Context, req PlayTimel
 		return
 	}
 
+	matrix := MatrixFromConfig(systemConfig)
+
+	//	Draw through a frame buffer, so steps can blend into each other
+	//	(and remember what we were showing when we're done)
+	transitions := NewTransitionStrip(pixels, systemConfig.LEDs, matrix, bp.lastFrame.get())
+	defer func() {
+		transitions.SetFilters(ctx, nil)
+		bp.lastFrame.set(transitions.Showing())
+	}()
+
 	//	Create a new pixel array
-	arr := pixarray.NewPixArray(systemConfig.LEDs, systemConfig.NumberOfColors, pixels)
+	arr := pixarray.NewPixArray(systemConfig.LEDs, systemConfig.NumberOfColors, transitions)
 
 	//	Set the defaults for the StepProcessor:
 	sp := StepProcessor{
-		GPIO:           systemConfig.GPIO,
-		LEDs:           systemConfig.LEDs,
-		PixelOrder:     systemConfig.PixelOrder,
-		NumberOfColors: systemConfig.NumberOfColors,
-		PixArray:       arr,
+		GPIO:               systemConfig.GPIO,
+		LEDs:               systemConfig.LEDs,
+		PixelOrder:         systemConfig.PixelOrder,
+		NumberOfColors:     systemConfig.NumberOfColors,
+		PixArray:           arr,
+		MaxStrobeFrequency: bp.MaxStrobeFrequency,
+		Palettes:           palettes,
+		Matrix:             matrix,
+		ImageDir:           bp.ImageDir,
+		Images:             images,
+		Audio:              bp.Audio,
+		Frame:              transitions,
+		ScriptDir:          bp.ScriptDir,
+		ScriptFrameLimit:   bp.ScriptFrameLimit,
+		Rand:               rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 
+	//	Random values that are picked once per play (by step id, then path)
+	picked := map[string]map[string]any{}
+
 	//	Process the timeline
 	log.Debug().
 		Str("ProcessID", req.ProcessID).
@@ -218,13 +309,30 @@ func (bp *BackgroundProcess) StartTimelinePlay(cx context.Context, req PlayTimel
 		sp.GPIO = int(req.RequestedTimeline.GPIO.Int32)
 	}
 
+	//	How many more times each counted loop goes round (by the index of its loop step)
+	remaining := map[int]int{}
+
 	//	Iterate through each step
-loopstart:
-	for _, step := range req.RequestedTimeline.Steps {
+	steps := req.RequestedTimeline.Steps
+	for i := 0; i < len(steps); i++ {
+		step := steps[i]
 
 		select {
 		default:
 
+			//	Pick the step's random values
+			if len(step.Random) > 0 {
+				if picked[step.ID] == nil {
+					picked[step.ID] = map[string]any{}
+				}
+				meta, err := data.ResolveRandom(step.MetaInfo, step.Random, sp.Rand, picked[step.ID])
+				if err != nil {
+					log.Err(err).Str("stepid", step.ID).Msg("Problem picking random values.  Using the step as it is")
+				} else {
+					step.MetaInfo = meta
+				}
+			}
+
 			//	Find out what type of frame this is, and act accordingly:
 			switch step.Type {
 			case stepType.Unknown:
@@ -236,10 +344,40 @@ loopstart:
 
 			case stepType.Loop:
 				//	Get the loop information and process the loop:
-				log.Debug().Str("stepid", step.ID).Int32("time", step.Time.Int32).Msg("Processing loop")
+				meta, _ := step.MetaInfo.(data.LoopMeta)
+				target, err := data.LoopTarget(steps, i)
+				if err != nil {
+					log.Err(err).Str("stepid", step.ID).Msg("Problem with loop.  Skipping it")
+					continue
+				}
+
+				//	If the loop has a count, see if it has gone round enough times.  Once it
+				//	has, it starts counting again (so loops inside other loops work)
+				if meta.Count > 0 {
+					if _, counting := remaining[i]; !counting {
+						remaining[i] = meta.Count
+					}
+					remaining[i]--
 
-				//	Go to the loopstart label
-				goto loopstart
+					if remaining[i] <= 0 {
+						delete(remaining, i)
+						log.Debug().Str("stepid", step.ID).Int("count", meta.Count).Msg("Loop finished")
+						continue
+					}
+				}
+
+				log.Debug().
+					Str("stepid", step.ID).
+					Int("count", meta.Count).
+					Int("remaining", remaining[i]).
+					Int("target", target+1).
+					Msg("Processing loop")
+
+				//	Go back to the target step
+				i = target - 1
+
+			case stepType.Label:
+				log.Debug().Str("stepid", step.ID).Msg("Passing label")
 
 			case stepType.Trigger:
 				sp.ProcessTrigger(step)
@@ -261,7 +399,7 @@ loopstart:
 
 			case stepType.RandomSleep:
 				//	Calculate our sleep time
-				sleepTime := rand.Intn(int(step.Time.Int32)) //	Calculate sleep time from the passed maximum time in the step
+				sleepTime := sp.Rand.Intn(int(step.Time.Int32)) //	Calculate sleep time from the passed maximum time in the step
 
 				log.Debug().
 					Str("stepid", step.ID).
@@ -279,22 +417,31 @@ loopstart:
 				}
 
 			case stepType.Effect:
+				//	Run the step's filters (then the timeline's) over everything the effect draws
+				filters := append(append([]data.StepFilter{}, step.Filters...), req.RequestedTimeline.Filters...)
+				filters = sp.useWhiteChannel(filters).([]data.StepFilter)
+				transitions.SetFilters(ctx, NewFilterChain(filters, sp.LEDs, sp.Matrix, rand.New(rand.NewSource(sp.Rand.Int63())), time.Now()))
+
+				//	If the step blends in from the previous one, start the transition
+				if step.Transition != nil && step.Transition.Duration > 0 {
+					log.Debug().
+						Str("stepid", step.ID).
+						Str("type", step.Transition.Type).
+						Int("duration", step.Transition.Duration).
+						Msg("Starting transition")
+					transitions.Begin(ctx, *step.Transition, sp.Rand)
+				}
+
+				//	Use the white channel for color temperatures (if we have one)
+				step.MetaInfo = sp.useWhiteChannel(step.MetaInfo)
+
 				//	Find the effect type and process it.
 				switch step.Effect {
 				case effect.Fade:
 					sp.ProcessFadeEffect(ctx, step)
 
 				case effect.Gradient:
-					sp.ProcessGradientEffect(step)
-
-					//	Sleep for the time specified
-					//	(this has the effect of showing the gradient for this amount of time)
-					select {
-					case <-time.After(time.Duration(step.Time.Int32) * time.Millisecond):
-						continue
-					case <-ctx.Done():
-						return
-					}
+					sp.ProcessGradientEffect(ctx, step)
 
 				case effect.KnightRider:
 					// This will continue indefinitely.  We need to come up with a
@@ -334,6 +481,60 @@ loopstart:
 				case effect.Zip:
 					sp.ProcessZipEffect(ctx, step)
 
+				case effect.Plasma:
+					sp.ProcessPlasmaEffect(ctx, step)
+
+				case effect.Noise:
+					sp.ProcessNoiseEffect(ctx, step)
+
+				case effect.Candle:
+					sp.ProcessCandleEffect(ctx, step)
+
+				case effect.BouncingBalls:
+					sp.ProcessBouncingBallsEffect(ctx, step)
+
+				case effect.Strobe:
+					sp.ProcessStrobeEffect(ctx, step)
+
+				case effect.Beacon:
+					sp.ProcessBeaconEffect(ctx, step)
+
+				case effect.Image:
+					sp.ProcessImageEffect(ctx, step)
+
+				case effect.Gif:
+					sp.ProcessGifEffect(ctx, step)
+
+				case effect.Text:
+					sp.ProcessTextEffect(ctx, step)
+
+				case effect.Clock:
+					sp.ProcessClockEffect(ctx, step)
+
+				case effect.Countdown:
+					sp.ProcessCountdownEffect(ctx, step)
+
+				case effect.VUMeter:
+					sp.ProcessVUMeterEffect(ctx, step)
+
+				case effect.Spectrum:
+					sp.ProcessSpectrumEffect(ctx, step)
+
+				case effect.BeatPulse:
+					sp.ProcessBeatPulseEffect(ctx, step)
+
+				case effect.Formula:
+					sp.ProcessFormulaEffect(ctx, step)
+
+				case effect.Script:
+					sp.ProcessScriptEffect(ctx, step)
+
+				case effect.Sunrise, effect.Sunset:
+					sp.ProcessSunriseEffect(ctx, step)
+
+				case effect.Circadian:
+					sp.ProcessCircadianEffect(ctx, step)
+
 				}
 
 			}
@@ -2,11 +2,13 @@ package leds
 
 import (
 	"context"
+	"fmt"
 	"github.com/Jon-Bright/ledctl/effects"
 	"github.com/Jon-Bright/ledctl/pixarray"
 	"github.com/danesparza/fxpixel/internal/data"
+	"github.com/danesparza/fxpixel/internal/data/const/effect"
 	"github.com/rs/zerolog/log"
-	"math/rand"
+	"strings"
 	"time"
 )
 
@@ -44,7 +46,7 @@ func (sp StepProcessor) ProcessSolidEffect(step data.TimelineStep) error {
 }
 
 // ProcessGradientEffect processes the passed gradient effect meta
-func (sp StepProcessor) ProcessGradientEffect(step data.TimelineStep) error {
+func (sp StepProcessor) ProcessGradientEffect(ctx context.Context, step data.TimelineStep) error {
 
 	//	Convert the meta information:
 	meta := step.MetaInfo.(data.GradientMeta)
@@ -55,25 +57,47 @@ func (sp StepProcessor) ProcessGradientEffect(step data.TimelineStep) error {
 		Int32("steptime", step.Time.Int32).
 		Any("startcolor", meta.StartColor).
 		Any("endcolor", meta.EndColor).
+		Any("stops", meta.Stops).
+		Str("palette", meta.Palette).
+		Str("colorspace", meta.ColorSpace).
+		Float64("speed", meta.Speed).
 		Msg("Processing effect: gradient")
 
-	var a Artist
+	//	A scrolling gradient needs to wrap around so it joins up smoothly
+	wrap := meta.Speed != 0
 
-	a = &Gradient{
-		Colors: [][]int{
-			{meta.StartColor.R, meta.StartColor.G, meta.StartColor.B, meta.StartColor.W},
-			{meta.EndColor.R, meta.EndColor.G, meta.EndColor.B, meta.EndColor.W},
-		},
+	//	Positioned stops win, then a stored palette, then start/end color
+	var colors palette
+	if len(meta.Stops) > 0 {
+		colors = newPaletteFromStops(meta.Stops, wrap)
+	} else {
+		colors = sp.findPalette(meta.Palette, nil, []data.MetaColor{meta.StartColor, meta.EndColor}, wrap)
 	}
+	colors.colorSpace = meta.ColorSpace
 
-	a.Draw(sp.PixArray)
+	gradient := NewPaletteGradient(colors, meta.Speed)
+
+	//	Scrolling gradients run for the step time (or until stopped)
+	if meta.Speed != 0 {
+		return sp.runEffect(ctx, gradient, time.Duration(step.Time.Int32)*time.Millisecond)
+	}
+
+	//	Static gradients only need to be drawn once
+	now := time.Now()
+	gradient.Start(sp.PixArray, now)
+	gradient.NextStep(sp.PixArray, now)
 
-	////	Write the data
 	err := sp.PixArray.Write()
 	if err != nil {
 		log.Err(err).Msg("Problem writing to strip")
 	}
 
+	//	Show the gradient for the time specified
+	select {
+	case <-time.After(time.Duration(step.Time.Int32) * time.Millisecond):
+	case <-ctx.Done():
+	}
+
 	return nil
 }
 
@@ -90,94 +114,54 @@ func (sp StepProcessor) ProcessLightningEffect(ctx context.Context, step data.Ti
 	//	"burst-type": "fixed", /* Optional: fixed/random - defaults to random */
 	//	"burst-spacing": 100, /* Optional: Maximum time (in ms) to space the bursts */
 	//	"burst-length": 300, /* Optional: Maximum time (in ms) to show each burst */
-	//	"burst-brightness": 128 /* Optional: Brightness of the white light for each burst */
+	//	"burst-brightness": 128, /* Optional: Maximum brightness of the white light for each burst */
+	//	"strike-size": 20, /* Optional: Number of pixels each strike lights.  Defaults to the whole strip */
+	//	"strike-falloff": 5 /* Optional: Number of pixels the strike fades out over at each edge */
 	//}
 
 	//	Set our defaults:
-	rand.Seed(time.Now().UnixNano())
-	defaultNumberOfBursts := rand.Intn(5)    //	Default number of bursts (if not specified)
-	defaultBurstSpacing := rand.Intn(5)      // Default time (in ms) to space the bursts (if not specified)
-	defaultBurstLength := rand.Intn(100)     // Default time (in ms) to show the bursts (if not specified)
-	defaultBurstBrightness := rand.Intn(128) // Default time (in ms) to show the bursts (if not specified)
-
-	if meta.Bursts == 0 {
-		meta.Bursts = defaultNumberOfBursts
+	if meta.Bursts <= 0 {
+		meta.Bursts = 4
 	}
 
-	if meta.BurstSpacing == 0 {
-		meta.BurstSpacing = defaultBurstSpacing
+	if meta.BurstSpacing <= 0 {
+		meta.BurstSpacing = 80
 	}
 
-	if meta.BurstLength == 0 {
-		meta.BurstLength = defaultBurstLength
+	if meta.BurstLength <= 0 {
+		meta.BurstLength = 100
 	}
 
-	if meta.BurstBrightness == 0 {
-		meta.BurstBrightness = defaultBurstBrightness
+	if meta.BurstBrightness <= 0 {
+		meta.BurstBrightness = 128
 	}
 
-	//	Create a lightning 'on' pixel based on the burst brightness
-	ln := pixarray.Pixel{
-		R: 0,
-		G: 0,
-		B: (meta.BurstBrightness + 1) / 2,
-		W: meta.BurstBrightness,
+	if meta.StrikeSize > 0 && meta.StrikeFalloff == 0 {
+		meta.StrikeFalloff = meta.StrikeSize / 4
 	}
 
-	//	Create an 'off' pixel (since we need to flash)
-	loff := pixarray.Pixel{}
+	fixed := strings.EqualFold(meta.BurstType, effect.BURST_FIXED)
 
 	//	Log the meta information we have:
 	log.Debug().
 		Str("stepid", step.ID).
 		Int32("steptime", step.Time.Int32).
-		Any("bursts", meta.Bursts).
-		Any("bursttype", meta.BurstType).
-		Any("bursttype", meta.BurstType).
-		Any("burstspacing", meta.BurstSpacing).
-		Any("burstlength", meta.BurstLength).
-		Any("burstbrightness", meta.BurstBrightness).
+		Int("bursts", meta.Bursts).
+		Bool("fixed", fixed).
+		Int("burstspacing", meta.BurstSpacing).
+		Int("burstlength", meta.BurstLength).
+		Int("burstbrightness", meta.BurstBrightness).
+		Int("strikesize", meta.StrikeSize).
+		Int("strikefalloff", meta.StrikeFalloff).
 		Msg("Processing effect: lightning")
 
-	//	Cycle through our bursts
-	for b := 0; b < meta.Bursts; b++ {
-
-		select {
-		default:
-
-			//	Lightning flash
-			sp.PixArray.SetAll(ln)
-			sp.PixArray.Write()
-
-			select {
-			case <-time.After(time.Duration(meta.BurstLength) * time.Millisecond):
-				//	Flash over
-				sp.PixArray.SetAll(loff)
-				sp.PixArray.Write()
-
-				//	Add burst spacing
-				select {
-				case <-time.After(time.Duration(meta.BurstSpacing) * time.Millisecond):
-					continue
-				case <-ctx.Done():
-					return nil
-				}
-
-			case <-ctx.Done():
-				return nil
-			}
-
-		case <-ctx.Done():
-			//	Reset all pixels:
-			sp.PixArray.SetAll(pixarray.Pixel{})
-			sp.PixArray.Write()
-
-			return nil
-		}
+	lightning := NewLightning(meta.Bursts, fixed,
+		time.Duration(meta.BurstSpacing)*time.Millisecond,
+		time.Duration(meta.BurstLength)*time.Millisecond,
+		clampChannel(float64(meta.BurstBrightness)),
+		meta.StrikeSize, meta.StrikeFalloff, sp.Rand)
 
-	}
-
-	return nil
+	return sp.runEffect(ctx, lightning, 0)
 }
 
 // ProcessSequenceEffect processes the passed sequence effect meta
@@ -231,45 +215,53 @@ func (sp StepProcessor) ProcessFadeEffect(ctx context.Context, step data.Timelin
 		Str("stepid", step.ID).
 		Int32("steptime", step.Time.Int32).
 		Any("color", meta.Color).
+		Str("easing", meta.Easing).
+		Any("fromcolor", meta.FromColor).
+		Str("from", meta.From).
+		Any("sequence", meta.Sequence).
+		Any("stops", meta.Stops).
+		Str("palette", meta.Palette).
 		Msg("Processing effect: fade")
 
-	var d time.Duration
-
-	fade := effects.NewFade(time.Duration(step.Time.Int32)*time.Millisecond, pixarray.Pixel{
-		R: meta.Color.R,
-		G: meta.Color.G,
-		B: meta.Color.B,
-		W: meta.Color.W,
-	})
-
-	fade.Start(sp.PixArray, time.Now())
+	//	Figure out where each pixel starts.  Blank means wherever it is now
+	var from []pixarray.Pixel
+	switch {
+	case meta.FromColor != nil:
+		from = []pixarray.Pixel{{R: meta.FromColor.R, G: meta.FromColor.G, B: meta.FromColor.B, W: meta.FromColor.W}}
+	case strings.EqualFold(meta.From, effect.FADE_FROM_CURRENT) && sp.Frame != nil:
+		from = sp.Frame.Showing()
+	}
 
-	//	Create a ticker to process work:
-	ticker := time.NewTicker(1 * time.Millisecond)
-	for {
-		select {
-		case <-ticker.C:
-			d = fade.NextStep(sp.PixArray, time.Now())
-			err := sp.PixArray.Write()
-			if err != nil {
-				log.Err(err).Msg("Problem writing to strip")
-			}
+	//	... and where it ends up.  A gradient wins, then a sequence, then the color
+	var to []pixarray.Pixel
+	switch {
+	case len(meta.Stops) > 0 || meta.Palette != "":
+		var colors palette
+		if len(meta.Stops) > 0 {
+			colors = newPaletteFromStops(meta.Stops, false)
+		} else {
+			colors = sp.findPalette(meta.Palette, nil, []data.MetaColor{meta.Color}, false)
+		}
+		colors.colorSpace = meta.ColorSpace
 
-			//	This is a weird way to signal this,
-			//	but a duration of 0 means the fade is 'done'
-			if d == 0 {
-				break
+		for i := 0; i < sp.LEDs; i++ {
+			t := 0.0
+			if sp.LEDs > 1 {
+				t = float64(i) / float64(sp.LEDs-1)
 			}
-		case <-ctx.Done():
-			//	Reset all pixels:
-			sp.PixArray.SetAll(pixarray.Pixel{})
-			sp.PixArray.Write()
-
-			return nil
+			to = append(to, colors.colorAt(t))
+		}
+	case len(meta.Sequence) > 0:
+		for _, c := range meta.Sequence {
+			to = append(to, pixarray.Pixel{R: c.R, G: c.G, B: c.B, W: c.W})
 		}
+	default:
+		to = []pixarray.Pixel{{R: meta.Color.R, G: meta.Color.G, B: meta.Color.B, W: meta.Color.W}}
 	}
 
-	return nil
+	fade := NewFade(time.Duration(step.Time.Int32)*time.Millisecond, from, to, easing(meta.Easing, effect.EASE_LINEAR))
+
+	return sp.runEffect(ctx, fade, 0)
 }
 
 // ProcessKnightRiderEffect processes the knight rider effect
@@ -304,8 +296,7 @@ func (sp StepProcessor) ProcessKnightRiderEffect(ctx context.Context, step data.
 			}
 		case <-ctx.Done():
 			//	Reset all pixels:
-			sp.PixArray.SetAll(pixarray.Pixel{})
-			sp.PixArray.Write()
+			sp.reset(ctx)
 
 			return nil
 		}
@@ -342,15 +333,14 @@ func (sp StepProcessor) ProcessRainbowEffect(ctx context.Context, step data.Time
 
 		case <-ctx.Done():
 			//	Reset all pixels:
-			sp.PixArray.SetAll(pixarray.Pixel{})
-			sp.PixArray.Write()
+			sp.reset(ctx)
 
 			return nil
 		}
 	}
 }
 
-// ProcessZipEffect processes the rainbow effect
+// ProcessZipEffect processes the zip effect
 func (sp StepProcessor) ProcessZipEffect(ctx context.Context, step data.TimelineStep) error {
 
 	//	Convert the meta information:
@@ -361,45 +351,843 @@ func (sp StepProcessor) ProcessZipEffect(ctx context.Context, step data.Timeline
 		Str("stepid", step.ID).
 		Int32("steptime", step.Time.Int32).
 		Any("color", meta.Color).
+		Str("easing", meta.Easing).
 		Msg("Processing effect: zip")
 
-	var d time.Duration
-
 	//	Use the time from the step, but default to 2 seconds if it's not set
 	zipDuration := int(step.Time.Int32)
 	if zipDuration == 0 {
 		zipDuration = 2000
 	}
 
-	zip := effects.NewZip(time.Duration(zipDuration)*time.Millisecond, pixarray.Pixel{
+	zip := NewZip(time.Duration(zipDuration)*time.Millisecond, pixarray.Pixel{
 		R: meta.Color.R,
 		G: meta.Color.G,
 		B: meta.Color.B,
 		W: meta.Color.W,
-	})
+	}, easing(meta.Easing, effect.EASE_LINEAR))
+
+	return sp.runEffect(ctx, zip, 0)
+}
+
+// ProcessPlasmaEffect processes the passed plasma effect meta
+func (sp StepProcessor) ProcessPlasmaEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.NoiseMeta)
+
+	//	Set our defaults:
+	if meta.Scale == 0 {
+		meta.Scale = 0.03
+	}
 
-	zip.Start(sp.PixArray, time.Now())
+	if meta.Speed == 0 {
+		meta.Speed = 0.3
+	}
+
+	if meta.Octaves <= 0 {
+		meta.Octaves = 2
+	}
+	if meta.Octaves > data.MaxNoiseOctaves {
+		meta.Octaves = data.MaxNoiseOctaves
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Float64("scale", meta.Scale).
+		Float64("speed", meta.Speed).
+		Int("octaves", meta.Octaves).
+		Any("colors", meta.Colors).
+		Str("palette", meta.Palette).
+		Msg("Processing effect: plasma")
+
+	plasma := NewPlasma(meta.Scale, meta.Speed, meta.Octaves, sp.findPalette(meta.Palette, meta.Colors, rainbowColors, true), sp.Matrix)
+
+	return sp.runEffect(ctx, plasma, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// ProcessNoiseEffect processes the passed noise effect meta
+func (sp StepProcessor) ProcessNoiseEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.NoiseMeta)
+
+	//	Set our defaults:
+	if meta.Scale == 0 {
+		meta.Scale = 0.05
+	}
+
+	if meta.Speed == 0 {
+		meta.Speed = 0.2
+	}
+
+	if meta.Octaves <= 0 {
+		meta.Octaves = 3
+	}
+	if meta.Octaves > data.MaxNoiseOctaves {
+		meta.Octaves = data.MaxNoiseOctaves
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Float64("scale", meta.Scale).
+		Float64("speed", meta.Speed).
+		Int("octaves", meta.Octaves).
+		Any("colors", meta.Colors).
+		Str("palette", meta.Palette).
+		Msg("Processing effect: noise")
+
+	noise := NewNoise(meta.Scale, meta.Speed, meta.Octaves, sp.findPalette(meta.Palette, meta.Colors, lavaColors, false), sp.Matrix)
+
+	return sp.runEffect(ctx, noise, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// reset turns all the pixels off.  The filters are cleared first, so the strip goes
+// dark (rather than, say, flashing white through an invert filter)
+func (sp StepProcessor) reset(ctx context.Context) {
+	if sp.Frame != nil {
+		sp.Frame.SetFilters(ctx, nil)
+	}
+	sp.PixArray.SetAll(pixarray.Pixel{})
+	sp.PixArray.Write()
+}
+
+// runEffect draws the passed effect frame by frame until the effect reports it is
+// done, the run time has passed (a run time of 0 runs until stopped) or the context
+// is cancelled
+func (sp StepProcessor) runEffect(ctx context.Context, e effects.Effect, runTime time.Duration) error {
+
+	//	If we have a run time, set a timer for it
+	var finished <-chan time.Time
+	if runTime > 0 {
+		timer := time.NewTimer(runTime)
+		defer timer.Stop()
+		finished = timer.C
+	}
+
+	e.Start(sp.PixArray, time.Now())
 
 	//	Create a ticker to process work:
 	ticker := time.NewTicker(1 * time.Millisecond)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ticker.C:
-			d = zip.NextStep(sp.PixArray, time.Now())
-			sp.PixArray.Write()
+			d := e.NextStep(sp.PixArray, time.Now())
+			err := sp.PixArray.Write()
+			if err != nil {
+				log.Err(err).Msg("Problem writing to strip")
+			}
 
-			//	This is a weird way to signal this,
-			//	but a duration of 0 means the fade is 'done'
+			//	A duration of 0 means the effect is 'done'
 			if d == 0 {
 				return nil
 			}
 
+		case <-finished:
+			return nil
+
 		case <-ctx.Done():
 			//	Reset all pixels:
-			sp.PixArray.SetAll(pixarray.Pixel{})
-			sp.PixArray.Write()
+			sp.reset(ctx)
 
 			return nil
 		}
 	}
 }
+
+// ProcessCandleEffect processes the passed candle effect meta
+func (sp StepProcessor) ProcessCandleEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.CandleMeta)
+
+	//	Set our defaults:
+	if meta.Temperature == 0 {
+		meta.Temperature = 1800
+	}
+
+	if meta.Brightness == 0 {
+		meta.Brightness = 200
+	}
+
+	if meta.Intensity == 0 {
+		meta.Intensity = 0.4
+	}
+
+	if meta.GroupSize <= 0 {
+		meta.GroupSize = 1
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Int("temperature", meta.Temperature).
+		Int("brightness", meta.Brightness).
+		Float64("intensity", meta.Intensity).
+		Float64("wind", meta.Wind).
+		Int("groupsize", meta.GroupSize).
+		Msg("Processing effect: candle")
+
+	candle := NewCandle(meta.Temperature, meta.Brightness, meta.Intensity, meta.Wind, meta.GroupSize, sp.Rand)
+
+	return sp.runEffect(ctx, candle, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// ProcessBouncingBallsEffect processes the passed bouncing balls effect meta
+func (sp StepProcessor) ProcessBouncingBallsEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.BouncingBallsMeta)
+
+	//	Set our defaults:
+	if len(meta.Colors) == 0 {
+		meta.Colors = rainbowColors
+	}
+
+	if meta.Balls <= 0 {
+		meta.Balls = len(meta.Colors)
+	}
+
+	if meta.Gravity == 0 {
+		meta.Gravity = 9.81
+	}
+
+	if meta.Dampening == 0 {
+		meta.Dampening = 0.1
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Int("balls", meta.Balls).
+		Any("colors", meta.Colors).
+		Float64("gravity", meta.Gravity).
+		Float64("dampening", meta.Dampening).
+		Bool("relaunch", meta.Relaunch).
+		Msg("Processing effect: bouncing balls")
+
+	//	Give each ball a color (repeating the colors if we need to)
+	colors := []pixarray.Pixel{}
+	for b := 0; b < meta.Balls; b++ {
+		c := meta.Colors[b%len(meta.Colors)]
+		colors = append(colors, pixarray.Pixel{R: c.R, G: c.G, B: c.B, W: c.W})
+	}
+
+	balls := NewBouncingBalls(colors, meta.Gravity, meta.Dampening, meta.Relaunch, sp.Rand)
+
+	return sp.runEffect(ctx, balls, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// ProcessStrobeEffect processes the passed strobe effect meta
+func (sp StepProcessor) ProcessStrobeEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.StrobeMeta)
+
+	//	Set our defaults:
+	if meta.Color == (data.MetaColor{}) {
+		meta.Color = data.MetaColor{R: 255, G: 255, B: 255, W: 255}
+	}
+
+	if meta.Frequency <= 0 {
+		meta.Frequency = 10
+	}
+
+	if meta.DutyCycle <= 0 || meta.DutyCycle > 1 {
+		meta.DutyCycle = 0.2
+	}
+
+	//	Respect the system wide safety cap
+	if sp.MaxStrobeFrequency > 0 && meta.Frequency > sp.MaxStrobeFrequency {
+		log.Warn().
+			Str("stepid", step.ID).
+			Float64("frequency", meta.Frequency).
+			Float64("maxfrequency", sp.MaxStrobeFrequency).
+			Msg("Strobe frequency is above the system maximum.  Using the system maximum")
+
+		meta.Frequency = sp.MaxStrobeFrequency
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Any("color", meta.Color).
+		Float64("frequency", meta.Frequency).
+		Float64("dutycycle", meta.DutyCycle).
+		Int("flashes", meta.Flashes).
+		Msg("Processing effect: strobe")
+
+	strobe := NewStrobe(pixarray.Pixel{
+		R: meta.Color.R,
+		G: meta.Color.G,
+		B: meta.Color.B,
+		W: meta.Color.W,
+	}, meta.Frequency, meta.DutyCycle, meta.Flashes)
+
+	err := sp.runEffect(ctx, strobe, time.Duration(step.Time.Int32)*time.Millisecond)
+
+	//	Don't leave the strip lit if we ran out of time mid-flash
+	sp.PixArray.SetAll(pixarray.Pixel{})
+	sp.PixArray.Write()
+
+	return err
+}
+
+// ProcessBeaconEffect processes the passed beacon effect meta
+func (sp StepProcessor) ProcessBeaconEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.BeaconMeta)
+
+	//	Set our defaults:
+	if meta.Preset == "" {
+		meta.Preset = effect.BEACON_POLICE
+	}
+
+	if meta.Period <= 0 {
+		meta.Period = 1000
+	}
+
+	//	The step's leds (if set) are where the pattern plays, unless a segment says otherwise
+	stepLeds := step.Leds.String
+
+	//	If we don't have a custom pattern, use the preset (split across the step's leds)
+	if len(meta.Segments) == 0 {
+		firstHalf, secondHalf := stepLeds, stepLeds
+		if pixels := parseLedRange(stepLeds, sp.LEDs); len(pixels) > 1 {
+			firstHalf = ledRangeString(pixels[:len(pixels)/2])
+			secondHalf = ledRangeString(pixels[len(pixels)/2:])
+		}
+
+		switch strings.ToLower(meta.Preset) {
+		case effect.BEACON_AMBER:
+			meta.Segments = []data.BeaconSegment{
+				{Leds: stepLeds, Colors: []data.MetaColor{{R: 255, G: 100}}, Rotate: true},
+			}
+		case effect.BEACON_WIGWAG:
+			meta.Segments = []data.BeaconSegment{
+				{Leds: firstHalf, Colors: []data.MetaColor{{R: 255, G: 255, B: 255}, {}}},
+				{Leds: secondHalf, Colors: []data.MetaColor{{}, {R: 255, G: 255, B: 255}}},
+			}
+		case effect.BEACON_POLICE, "":
+			meta.Segments = []data.BeaconSegment{
+				{Leds: firstHalf, Colors: []data.MetaColor{{R: 255}, {}}, Flashes: 3},
+				{Leds: secondHalf, Colors: []data.MetaColor{{}, {B: 255}}, Flashes: 3},
+			}
+		default:
+			err := fmt.Errorf("unknown beacon preset %q", meta.Preset)
+			log.Err(err).Str("stepid", step.ID).Msg("Problem with the beacon.  Skipping step")
+			return err
+		}
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Str("preset", meta.Preset).
+		Int("period", meta.Period).
+		Any("segments", meta.Segments).
+		Msg("Processing effect: beacon")
+
+	//	Build the segments
+	segments := []BeaconSegment{}
+	for _, s := range meta.Segments {
+		colors := []pixarray.Pixel{}
+		for _, c := range s.Colors {
+			colors = append(colors, pixarray.Pixel{R: c.R, G: c.G, B: c.B, W: c.W})
+		}
+
+		leds := s.Leds
+		if leds == "" {
+			leds = stepLeds
+		}
+
+		segments = append(segments, BeaconSegment{
+			Leds:    leds,
+			Colors:  colors,
+			Flashes: s.Flashes,
+			Offset:  time.Duration(s.Offset) * time.Millisecond,
+			Rotate:  s.Rotate,
+		})
+	}
+
+	beacon := NewBeacon(time.Duration(meta.Period)*time.Millisecond, segments)
+
+	return sp.runEffect(ctx, beacon, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// ProcessImageEffect processes the passed image effect meta
+func (sp StepProcessor) ProcessImageEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.ImageMeta)
+
+	//	Set our defaults:
+	if meta.Rate <= 0 {
+		meta.Rate = 30
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Str("image", meta.Image).
+		Float64("rate", meta.Rate).
+		Int("loops", meta.Loops).
+		Bool("reverse", meta.Reverse).
+		Msg("Processing effect: image")
+
+	//	Find the image in the image store
+	imagePath, err := sp.findImage(meta.Image)
+	if err != nil {
+		log.Warn().Str("image", meta.Image).Msg("Image not found.  Skipping step")
+		return err
+	}
+
+	img, err := loadImage(imagePath)
+	if err != nil {
+		log.Err(err).Str("image", meta.Image).Msg("Problem loading image")
+		return err
+	}
+
+	player := NewImagePlayer(img, sp.Matrix, meta.Rate, meta.Loops, meta.Reverse)
+
+	return sp.runEffect(ctx, player, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// ProcessGifEffect processes the passed gif effect meta
+func (sp StepProcessor) ProcessGifEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.GifMeta)
+
+	//	Set our defaults:
+	if meta.Scale == "" {
+		meta.Scale = effect.GIF_FIT
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Str("image", meta.Image).
+		Int("loops", meta.Loops).
+		Str("scale", meta.Scale).
+		Msg("Processing effect: gif")
+
+	if !sp.Matrix.IsSet() {
+		log.Warn().Msg("No matrix layout is configured.  Playing the gif as a single row")
+	}
+
+	//	Find the gif in the image store
+	imagePath, err := sp.findImage(meta.Image)
+	if err != nil {
+		log.Warn().Str("image", meta.Image).Msg("Image not found.  Skipping step")
+		return err
+	}
+
+	g, err := loadGif(imagePath)
+	if err != nil {
+		log.Err(err).Str("image", meta.Image).Msg("Problem loading gif")
+		return err
+	}
+
+	player := NewGifPlayer(g, sp.Matrix, meta.Loops, meta.Scale)
+
+	return sp.runEffect(ctx, player, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// ProcessTextEffect processes the passed text effect meta
+func (sp StepProcessor) ProcessTextEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.TextMeta)
+
+	//	Set our defaults:
+	if meta.Font == "" {
+		meta.Font = effect.FONT_5X7
+	}
+
+	if meta.Speed <= 0 {
+		meta.Speed = 10
+	}
+
+	if meta.Direction == "" {
+		meta.Direction = effect.TEXT_LEFT
+	}
+
+	if meta.Color == (data.MetaColor{}) {
+		meta.Color = data.MetaColor{R: 255, G: 255, B: 255}
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Str("text", meta.Text).
+		Str("font", meta.Font).
+		Any("color", meta.Color).
+		Any("colors", meta.Colors).
+		Str("palette", meta.Palette).
+		Float64("speed", meta.Speed).
+		Str("direction", meta.Direction).
+		Int("repeat", meta.Repeat).
+		Msg("Processing effect: text")
+
+	if !sp.Matrix.IsSet() {
+		log.Warn().Msg("No matrix layout is configured.  Showing the text as a single row")
+	}
+
+	colors := sp.findPalette(meta.Palette, meta.Colors, []data.MetaColor{meta.Color}, false)
+
+	text := NewScrollingText(meta.Text, findFont(meta.Font), colors, meta.Speed, meta.Direction, meta.Repeat, sp.Matrix)
+
+	return sp.runEffect(ctx, text, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// ProcessClockEffect processes the passed clock effect meta
+func (sp StepProcessor) ProcessClockEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.ClockMeta)
+
+	//	Set our defaults:
+	if meta.HourColor == (data.MetaColor{}) {
+		meta.HourColor = data.MetaColor{R: 255}
+	}
+
+	if meta.MinuteColor == (data.MetaColor{}) {
+		meta.MinuteColor = data.MetaColor{G: 255}
+	}
+
+	if meta.SecondColor == (data.MetaColor{}) {
+		meta.SecondColor = data.MetaColor{B: 255}
+	}
+
+	if meta.Format == "" {
+		meta.Format = effect.CLOCK_24H
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Any("hourcolor", meta.HourColor).
+		Any("minutecolor", meta.MinuteColor).
+		Any("secondcolor", meta.SecondColor).
+		Any("markercolor", meta.MarkerColor).
+		Str("format", meta.Format).
+		Str("font", meta.Font).
+		Str("timezone", meta.Timezone).
+		Int("offset", meta.Offset).
+		Bool("reverse", meta.Reverse).
+		Msg("Processing effect: clock")
+
+	location := time.Local
+	if meta.Timezone != "" {
+		loc, err := time.LoadLocation(meta.Timezone)
+		if err != nil {
+			log.Warn().Err(err).Str("timezone", meta.Timezone).Msg("Unknown timezone.  Using local time")
+		} else {
+			location = loc
+		}
+	}
+
+	colors := ClockColors{
+		Hour:   pixarray.Pixel{R: meta.HourColor.R, G: meta.HourColor.G, B: meta.HourColor.B, W: meta.HourColor.W},
+		Minute: pixarray.Pixel{R: meta.MinuteColor.R, G: meta.MinuteColor.G, B: meta.MinuteColor.B, W: meta.MinuteColor.W},
+		Second: pixarray.Pixel{R: meta.SecondColor.R, G: meta.SecondColor.G, B: meta.SecondColor.B, W: meta.SecondColor.W},
+		Marker: pixarray.Pixel{R: meta.MarkerColor.R, G: meta.MarkerColor.G, B: meta.MarkerColor.B, W: meta.MarkerColor.W},
+	}
+
+	clock := NewClock(colors, strings.ToLower(meta.Format) == effect.CLOCK_12H, findFont(meta.Font), location, sp.Matrix, meta.Offset, meta.Reverse)
+
+	return sp.runEffect(ctx, clock, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// ProcessCountdownEffect processes the passed countdown effect meta
+func (sp StepProcessor) ProcessCountdownEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.CountdownMeta)
+
+	//	Set our defaults:
+	if meta.Color == (data.MetaColor{}) {
+		meta.Color = data.MetaColor{R: 255, G: 255, B: 255}
+	}
+
+	if meta.Duration <= 0 {
+		meta.Duration = int(step.Time.Int32)
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Str("target", meta.Target).
+		Int("duration", meta.Duration).
+		Any("color", meta.Color).
+		Str("font", meta.Font).
+		Bool("reverse", meta.Reverse).
+		Msg("Processing effect: countdown")
+
+	//	Figure out what we're counting down to
+	target := time.Now().Add(time.Duration(meta.Duration) * time.Millisecond)
+	if meta.Target != "" {
+		parsed, err := time.Parse(time.RFC3339, meta.Target)
+		if err != nil {
+			log.Err(err).Str("target", meta.Target).Msg("Problem parsing countdown target")
+			return fmt.Errorf("problem parsing countdown target: %v", err)
+		}
+		target = parsed
+	}
+
+	if !target.After(time.Now()) {
+		log.Warn().Time("target", target).Msg("Countdown target has already passed.  Skipping step")
+		return nil
+	}
+
+	color := pixarray.Pixel{R: meta.Color.R, G: meta.Color.G, B: meta.Color.B, W: meta.Color.W}
+	countdown := NewCountdown(target, color, findFont(meta.Font), sp.Matrix, meta.Reverse)
+
+	//	A countdown to a target runs until the target (or until stopped)
+	runTime := time.Duration(step.Time.Int32) * time.Millisecond
+	if meta.Target != "" {
+		runTime = 0
+	}
+
+	return sp.runEffect(ctx, countdown, runTime)
+}
+
+// ProcessVUMeterEffect processes the passed vu meter effect meta
+func (sp StepProcessor) ProcessVUMeterEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.VUMeterMeta)
+
+	//	Set our defaults:
+	if meta.Sensitivity <= 0 {
+		meta.Sensitivity = 1
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Any("colors", meta.Colors).
+		Str("palette", meta.Palette).
+		Any("peakcolor", meta.PeakColor).
+		Bool("mirror", meta.Mirror).
+		Float64("sensitivity", meta.Sensitivity).
+		Msg("Processing effect: vu-meter")
+
+	if sp.Audio == nil {
+		log.Warn().Msg("No audio input is configured.  Skipping step")
+		return fmt.Errorf("no audio input is configured")
+	}
+
+	peakColor := pixarray.Pixel{R: meta.PeakColor.R, G: meta.PeakColor.G, B: meta.PeakColor.B, W: meta.PeakColor.W}
+	vu := NewVUMeter(sp.Audio, sp.findPalette(meta.Palette, meta.Colors, vuColors, false), peakColor, meta.Mirror, meta.Sensitivity)
+
+	return sp.runEffect(ctx, vu, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// ProcessSpectrumEffect processes the passed spectrum effect meta
+func (sp StepProcessor) ProcessSpectrumEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.SpectrumMeta)
+
+	//	Set our defaults:
+	if meta.Sensitivity <= 0 {
+		meta.Sensitivity = 1
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Any("colors", meta.Colors).
+		Str("palette", meta.Palette).
+		Float64("sensitivity", meta.Sensitivity).
+		Msg("Processing effect: spectrum")
+
+	if sp.Audio == nil {
+		log.Warn().Msg("No audio input is configured.  Skipping step")
+		return fmt.Errorf("no audio input is configured")
+	}
+
+	spectrum := NewSpectrum(sp.Audio, sp.findPalette(meta.Palette, meta.Colors, rainbowColors, false), meta.Sensitivity, sp.Matrix)
+
+	return sp.runEffect(ctx, spectrum, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// ProcessBeatPulseEffect processes the passed beat pulse effect meta
+func (sp StepProcessor) ProcessBeatPulseEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.BeatPulseMeta)
+
+	//	Set our defaults:
+	if meta.Decay <= 0 {
+		meta.Decay = 300
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Any("colors", meta.Colors).
+		Str("palette", meta.Palette).
+		Int("decay", meta.Decay).
+		Msg("Processing effect: beat-pulse")
+
+	if sp.Audio == nil {
+		log.Warn().Msg("No audio input is configured.  Skipping step")
+		return fmt.Errorf("no audio input is configured")
+	}
+
+	pulse := NewBeatPulse(sp.Audio, sp.findPalette(meta.Palette, meta.Colors, rainbowColors, false), time.Duration(meta.Decay)*time.Millisecond)
+
+	return sp.runEffect(ctx, pulse, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// ProcessFormulaEffect processes the passed formula effect meta
+func (sp StepProcessor) ProcessFormulaEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.FormulaMeta)
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Str("formula", meta.Formula).
+		Msg("Processing effect: formula")
+
+	program, err := compileFormula(meta.Formula)
+	if err != nil {
+		log.Err(err).Str("stepid", step.ID).Msg("Problem compiling formula.  Skipping step")
+		return err
+	}
+
+	program.SetRand(sp.Rand)
+	f := NewFormula(program, sp.Audio, sp.Matrix)
+
+	return sp.runEffect(ctx, f, time.Duration(step.Time.Int32)*time.Millisecond)
+}
+
+// ProcessScriptEffect processes the passed script effect meta
+func (sp StepProcessor) ProcessScriptEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.ScriptMeta)
+
+	//	Set our defaults:
+	if sp.ScriptFrameLimit <= 0 {
+		sp.ScriptFrameLimit = 50 * time.Millisecond
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Str("script", meta.Script).
+		Any("params", meta.Params).
+		Msg("Processing effect: script")
+
+	_, source, err := data.GetScript(sp.ScriptDir, meta.Script)
+	if err != nil {
+		log.Err(err).Str("stepid", step.ID).Msg("Problem loading script.  Skipping step")
+		return err
+	}
+
+	script := NewScript(meta.Script, source, meta.Params, sp.Audio, sp.Matrix, sp.ScriptFrameLimit)
+	defer script.Close()
+
+	if err := sp.runEffect(ctx, script, time.Duration(step.Time.Int32)*time.Millisecond); err != nil {
+		return err
+	}
+
+	return script.Err()
+}
+
+// ProcessSunriseEffect processes the passed sunrise (or sunset) effect meta
+func (sp StepProcessor) ProcessSunriseEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.SunriseMeta)
+
+	//	Set our defaults:
+	if meta.NightTemperature <= 0 {
+		meta.NightTemperature = 1000
+	}
+
+	if meta.DayTemperature <= 0 {
+		meta.DayTemperature = 6500
+	}
+
+	if meta.Brightness <= 0 {
+		meta.Brightness = 255
+	}
+
+	//	Use the time from the step, but default to 30 minutes if it's not set
+	duration := time.Duration(step.Time.Int32) * time.Millisecond
+	if duration <= 0 {
+		duration = 30 * time.Minute
+	}
+
+	sunset := step.Effect == effect.Sunset
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Int("nighttemperature", meta.NightTemperature).
+		Int("daytemperature", meta.DayTemperature).
+		Int("brightness", meta.Brightness).
+		Bool("sunset", sunset).
+		Msg("Processing effect: sunrise")
+
+	sunrise := NewSunrise(duration, meta.NightTemperature, meta.DayTemperature,
+		clampChannel(float64(meta.Brightness)), sunset, sp.NumberOfColors >= 4)
+
+	return sp.runEffect(ctx, sunrise, 0)
+}
+
+// ProcessCircadianEffect processes the passed circadian effect meta
+func (sp StepProcessor) ProcessCircadianEffect(ctx context.Context, step data.TimelineStep) error {
+
+	//	Convert the meta information:
+	meta := step.MetaInfo.(data.CircadianMeta)
+
+	//	Set our defaults:
+	if len(meta.Keyframes) == 0 {
+		meta.Keyframes = defaultCircadianKeyframes
+	}
+
+	//	Log the meta information we have:
+	log.Debug().
+		Str("stepid", step.ID).
+		Int32("steptime", step.Time.Int32).
+		Any("keyframes", meta.Keyframes).
+		Msg("Processing effect: circadian")
+
+	circadian, err := NewCircadian(meta.Keyframes, sp.NumberOfColors >= 4)
+	if err != nil {
+		log.Err(err).Str("stepid", step.ID).Msg("Problem with the circadian keyframes.  Skipping step")
+		return err
+	}
+
+	//	Keep following the day until the step time is up (or until stopped)
+	return sp.runEffect(ctx, circadian, time.Duration(step.Time.Int32)*time.Millisecond)
+}
@@ -0,0 +1,390 @@
+package leds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/audio"
+	"github.com/rs/zerolog/log"
+	lua "github.com/yuin/gopher-lua"
+	"math"
+	"runtime/metrics"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// scriptFrameInterval is how often a script renders a frame (about 60 frames a second)
+const scriptFrameInterval = 16 * time.Millisecond
+
+// scriptUnsafeGlobals are base library functions scripts don't get,
+// because they can reach the file system or load code we haven't checked
+var scriptUnsafeGlobals = []string{"dofile", "loadfile", "load", "loadstring", "require", "module", "_printregs"}
+
+// Limits that keep a script from using up the memory.  Strings the script builds with
+// string.rep are capped, and each call into the script can only allocate so much
+const (
+	scriptMaxRep        = 1 << 20  // Longest string (in bytes) string.rep will make
+	scriptMaxAllocation = 64 << 20 // Most a single call into the script can allocate (in bytes)
+)
+
+// scriptHeapAllocs is the runtime metric that counts the bytes allocated on the heap
+const scriptHeapAllocs = "/gc/heap/allocs:bytes"
+
+// Script runs a Lua script as an effect.  The script can define setup() (called once)
+// and render(frame, t) (called every frame).  See examples/script.jsonc
+type Script struct {
+	name       string
+	source     string
+	params     map[string]any
+	input      audio.Input
+	matrix     Matrix
+	frameLimit time.Duration
+	L          *lua.LState
+	pa         *pixarray.PixArray
+	frame      *lua.LTable
+	audio      *lua.LTable
+	render     lua.LValue
+	start      time.Time
+	next       time.Time
+	err        error
+}
+
+// NewScript creates a scripted effect.  Each call into the script has to finish
+// within the frame limit.  The audio input is optional
+func NewScript(name, source string, params map[string]any, input audio.Input, matrix Matrix, frameLimit time.Duration) *Script {
+	s := Script{}
+	s.name = name
+	s.source = source
+	s.params = params
+	s.input = input
+	s.matrix = matrix
+	s.frameLimit = frameLimit
+	return &s
+}
+
+// Err returns the error that stopped the script (if there was one)
+func (s *Script) Err() error {
+	return s.err
+}
+
+// Close releases the script's Lua state
+func (s *Script) Close() {
+	if s.L != nil {
+		s.L.Close()
+	}
+}
+
+func (s *Script) Start(pa *pixarray.PixArray, now time.Time) {
+	s.pa = pa
+	s.start = now
+	s.next = now
+
+	s.L = lua.NewState(lua.Options{
+		SkipOpenLibs:        true,
+		CallStackSize:       200,
+		RegistryMaxSize:     256 * 1024,
+		MinimizeStackMemory: true,
+	})
+
+	//	Only open the libraries that can't reach outside the script
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		s.L.Push(s.L.NewFunction(lib.open))
+		s.L.Push(lua.LString(lib.name))
+		s.L.Call(1, 0)
+	}
+	for _, name := range scriptUnsafeGlobals {
+		s.L.SetGlobal(name, lua.LNil)
+	}
+	if str, ok := s.L.GetGlobal(lua.StringLibName).(*lua.LTable); ok {
+		str.RawSetString("rep", s.L.NewFunction(luaRep))
+	}
+
+	s.L.SetGlobal("print", s.L.NewFunction(s.luaPrint))
+	s.L.SetGlobal("hsv", s.L.NewFunction(luaHSV))
+	s.L.SetGlobal("params", toLua(s.L, s.params))
+
+	s.frame = s.newFrame()
+	s.audio = s.L.NewTable()
+	s.L.SetGlobal("audio", s.audio)
+	s.updateAudio()
+
+	//	Run the script (to define its functions), then set it up
+	if err := s.call(func() error { return s.L.DoString(s.source) }); err != nil {
+		s.stop(err)
+		return
+	}
+
+	if setup := s.L.GetGlobal("setup"); setup.Type() == lua.LTFunction {
+		if err := s.call(func() error {
+			return s.L.CallByParam(lua.P{Fn: setup, NRet: 0, Protect: true}, s.frame)
+		}); err != nil {
+			s.stop(err)
+			return
+		}
+	}
+
+	s.render = s.L.GetGlobal("render")
+	if s.render.Type() != lua.LTFunction {
+		s.stop(fmt.Errorf("the script doesn't have a render(frame, t) function"))
+	}
+}
+
+func (s *Script) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	if s.err != nil {
+		return 0
+	}
+
+	//	Scripts render at a steady frame rate, not every tick
+	if now.Before(s.next) {
+		return s.next.Sub(now)
+	}
+	s.next = now.Add(scriptFrameInterval)
+
+	s.updateAudio()
+
+	t := lua.LNumber(now.Sub(s.start).Seconds())
+	if err := s.call(func() error {
+		return s.L.CallByParam(lua.P{Fn: s.render, NRet: 0, Protect: true}, s.frame, t)
+	}); err != nil {
+		s.stop(err)
+		return 0
+	}
+
+	return scriptFrameInterval
+}
+
+func (s *Script) Name() string {
+	return "SCRIPT"
+}
+
+// call calls into the script with the frame limit.  The call is also stopped if it
+// allocates too much memory
+func (s *Script) call(f func() error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.frameLimit)
+	defer cancel()
+
+	s.L.SetContext(ctx)
+	defer s.L.RemoveContext()
+
+	//	Keep an eye on the heap while the script runs.  The counter is for the whole
+	//	process, but nothing else allocates much while a frame renders
+	done := make(chan struct{})
+	defer close(done)
+	var tooBig atomic.Bool
+	go func() {
+		sample := []metrics.Sample{{Name: scriptHeapAllocs}}
+		metrics.Read(sample)
+		start := sample[0].Value.Uint64()
+
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				metrics.Read(sample)
+				if sample[0].Value.Uint64()-start > scriptMaxAllocation {
+					tooBig.Store(true)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err := f()
+	if err != nil && tooBig.Load() {
+		return fmt.Errorf("the script used more than %v MB of memory", scriptMaxAllocation>>20)
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("the script took longer than %v", s.frameLimit)
+	}
+	return err
+}
+
+// stop records the error that stopped the script
+func (s *Script) stop(err error) {
+	s.err = err
+	log.Err(err).Str("script", s.name).Msg("Script stopped")
+}
+
+func (s *Script) luaPrint(L *lua.LState) int {
+	args := []any{}
+	for i := 1; i <= L.GetTop(); i++ {
+		args = append(args, L.ToStringMeta(L.Get(i)).String())
+	}
+	log.Debug().Str("script", s.name).Msg(fmt.Sprint(args...))
+	return 0
+}
+
+// updateAudio copies the latest audio frame into the script's audio table
+func (s *Script) updateAudio() {
+	frame := audio.Frame{}
+	if s.input != nil {
+		frame = s.input.Latest()
+	}
+
+	s.audio.RawSetString("level", lua.LNumber(frame.Level))
+	s.audio.RawSetString("peak", lua.LNumber(frame.Peak))
+	s.audio.RawSetString("beat", lua.LBool(frame.Beat))
+	s.audio.RawSetString("beats", lua.LNumber(frame.Beats))
+
+	bands, ok := s.audio.RawGetString("bands").(*lua.LTable)
+	if !ok {
+		bands = s.L.NewTable()
+		s.audio.RawSetString("bands", bands)
+	}
+	for i, b := range frame.Bands {
+		bands.RawSetInt(i+1, lua.LNumber(b))
+	}
+}
+
+// newFrame creates the frame table scripts draw into.  Pixels are numbered from 0 (like
+// the rest of fxpixel) and colors are 0 - 255
+func (s *Script) newFrame() *lua.LTable {
+	n := s.pa.NumPixels()
+	width, height := n, 1
+	if s.matrix.IsSet() {
+		width, height = s.matrix.Width, s.matrix.Height
+	}
+
+	frame := s.L.NewTable()
+	frame.RawSetString("count", lua.LNumber(n))
+	frame.RawSetString("width", lua.LNumber(width))
+	frame.RawSetString("height", lua.LNumber(height))
+
+	channel := func(L *lua.LState, arg int) int {
+		return clampChannel(float64(L.OptNumber(arg, 0)))
+	}
+
+	s.L.SetFuncs(frame, map[string]lua.LGFunction{
+		//	frame:set(i, r, g, b [, w])
+		"set": func(L *lua.LState) int {
+			i := L.CheckInt(2)
+			if i >= 0 && i < n {
+				s.pa.SetOne(i, pixarray.Pixel{R: channel(L, 3), G: channel(L, 4), B: channel(L, 5), W: channel(L, 6)})
+			}
+			return 0
+		},
+		//	frame:hsv(i, h, s, v) with h, s and v 0 - 1
+		"hsv": func(L *lua.LState) int {
+			i := L.CheckInt(2)
+			if i >= 0 && i < n {
+				s.pa.SetOne(i, hsvPixel(float64(L.CheckNumber(3)), float64(L.OptNumber(4, 1)), float64(L.OptNumber(5, 1))))
+			}
+			return 0
+		},
+		//	r, g, b, w = frame:get(i)
+		"get": func(L *lua.LState) int {
+			i := L.CheckInt(2)
+			p := pixarray.Pixel{}
+			if i >= 0 && i < n {
+				p = s.pa.GetPixel(i)
+			}
+			L.Push(lua.LNumber(p.R))
+			L.Push(lua.LNumber(p.G))
+			L.Push(lua.LNumber(p.B))
+			L.Push(lua.LNumber(p.W))
+			return 4
+		},
+		//	frame:fill(r, g, b [, w])
+		"fill": func(L *lua.LState) int {
+			s.pa.SetAll(pixarray.Pixel{R: channel(L, 2), G: channel(L, 3), B: channel(L, 4), W: channel(L, 5)})
+			return 0
+		},
+		//	i = frame:xy(x, y).  Returns -1 if x/y is off the matrix
+		"xy": func(L *lua.LState) int {
+			x, y := L.CheckInt(2), L.OptInt(3, 0)
+			if x < 0 || x >= width || y < 0 || y >= height {
+				L.Push(lua.LNumber(-1))
+				return 1
+			}
+			i := x
+			if s.matrix.IsSet() {
+				i = s.matrix.Index(x, y)
+			}
+			L.Push(lua.LNumber(i))
+			return 1
+		},
+	})
+
+	return frame
+}
+
+// luaRep is string.rep(s, n [, sep]), but it won't make strings longer than scriptMaxRep
+func luaRep(L *lua.LState) int {
+	str := L.CheckString(1)
+	n := L.CheckInt(2)
+	sep := L.OptString(3, "")
+	if n <= 0 {
+		L.Push(lua.LString(""))
+		return 1
+	}
+
+	if int64(len(str)+len(sep))*int64(n) > scriptMaxRep {
+		L.RaiseError("string.rep can't make strings longer than %v bytes", scriptMaxRep)
+		return 0
+	}
+
+	L.Push(lua.LString(strings.Repeat(str+sep, n-1) + str))
+	return 1
+}
+
+// luaHSV converts h, s, v (0 - 1) to r, g, b (0 - 255)
+func luaHSV(L *lua.LState) int {
+	p := hsvPixel(float64(L.CheckNumber(1)), float64(L.OptNumber(2, 1)), float64(L.OptNumber(3, 1)))
+	L.Push(lua.LNumber(p.R))
+	L.Push(lua.LNumber(p.G))
+	L.Push(lua.LNumber(p.B))
+	return 3
+}
+
+// hsvPixel converts h, s, v (all 0 - 1) to a pixel.  The hue wraps around
+func hsvPixel(h, s, v float64) pixarray.Pixel {
+	h = frac(h)
+	if math.IsNaN(h) || math.IsInf(h, 0) {
+		h = 0
+	}
+	return hsvToRGB(h*360, clamp01(s), clamp01(v))
+}
+
+// toLua converts (decoded JSON) values to Lua values
+func toLua(L *lua.LState, value any) lua.LValue {
+	switch v := value.(type) {
+	case nil:
+		return lua.LNil
+	case bool:
+		return lua.LBool(v)
+	case float64:
+		return lua.LNumber(v)
+	case int:
+		return lua.LNumber(v)
+	case string:
+		return lua.LString(v)
+	case []any:
+		t := L.NewTable()
+		for _, item := range v {
+			t.Append(toLua(L, item))
+		}
+		return t
+	case map[string]any:
+		t := L.NewTable()
+		for key, item := range v {
+			t.RawSetString(key, toLua(L, item))
+		}
+		return t
+	}
+
+	return lua.LString(fmt.Sprint(value))
+}
@@ -0,0 +1,52 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"time"
+)
+
+// Strobe flashes the whole strip at a fixed frequency
+type Strobe struct {
+	color     pixarray.Pixel
+	frequency float64
+	dutyCycle float64
+	flashes   int
+	start     time.Time
+}
+
+// NewStrobe creates a strobe effect.  Frequency is in Hz, dutyCycle is the portion (0 - 1)
+// of each period the light is on, and flashes is the number of flashes (0 means keep flashing)
+func NewStrobe(color pixarray.Pixel, frequency, dutyCycle float64, flashes int) *Strobe {
+	s := Strobe{}
+	s.color = color
+	s.frequency = frequency
+	s.dutyCycle = dutyCycle
+	s.flashes = flashes
+	return &s
+}
+
+func (s *Strobe) Start(pa *pixarray.PixArray, now time.Time) {
+	s.start = now
+}
+
+func (s *Strobe) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	periods := now.Sub(s.start).Seconds() * s.frequency
+
+	//	If we've shown all our flashes, go dark and finish up
+	if s.flashes > 0 && int(periods) >= s.flashes {
+		pa.SetAll(pixarray.Pixel{})
+		return 0
+	}
+
+	if frac(periods) < s.dutyCycle {
+		pa.SetAll(s.color)
+	} else {
+		pa.SetAll(pixarray.Pixel{})
+	}
+
+	return time.Millisecond
+}
+
+func (s *Strobe) Name() string {
+	return "STROBE"
+}
@@ -0,0 +1,77 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/data"
+	"math"
+	"time"
+)
+
+// Sunrise slowly brightens the strip from dark to daylight, following the color of a
+// black body as it heats up.  A sunset runs the same curve backwards
+type Sunrise struct {
+	duration   time.Duration
+	night      int
+	day        int
+	brightness float64
+	reverse    bool
+	white      bool
+	start      time.Time
+}
+
+// NewSunrise creates a sunrise effect (or a sunset, if reverse is set).  Night and day are
+// color temperatures (in Kelvin) and brightness (0 - 255) is the brightness at the day end.
+// If white is set, the white channel is used for the white part of the color
+func NewSunrise(duration time.Duration, night, day, brightness int, reverse, white bool) *Sunrise {
+	s := Sunrise{}
+	s.duration = duration
+	s.night = night
+	s.day = day
+	s.brightness = float64(brightness) / 255.0
+	s.reverse = reverse
+	s.white = white
+	return &s
+}
+
+func (s *Sunrise) Start(pa *pixarray.PixArray, now time.Time) {
+	s.start = now
+}
+
+func (s *Sunrise) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	elapsed := now.Sub(s.start)
+	t := clamp01(elapsed.Seconds() / s.duration.Seconds())
+	if s.reverse {
+		t = 1 - t
+	}
+
+	//	Blend the color temperature in mireds, which changes at an even pace to the eye
+	night, day := 1e6/float64(s.night), 1e6/float64(s.day)
+	mired := night + (day-night)*t
+	color := data.KelvinColor(int(1e6 / mired))
+	if s.white {
+		color = whiteBalance(color)
+	}
+
+	pa.SetAll(Scale(pixarray.Pixel{R: color.R, G: color.G, B: color.B, W: color.W}, float32(s.brightness*lightness(t))))
+
+	if elapsed >= s.duration {
+		return 0
+	}
+
+	//	Changes are slow, so there's no need to redraw every tick
+	return 20 * time.Millisecond
+}
+
+func (s *Sunrise) Name() string {
+	return "SUNRISE"
+}
+
+// lightness returns the light output (0 - 1) for a perceived lightness of t (0 - 1), so
+// evenly spaced steps in t look evenly spaced.  This is the inverse of CIE L*
+func lightness(t float64) float64 {
+	l := t * 100
+	if l <= 8 {
+		return l / 903.3
+	}
+	return math.Pow((l+16)/116, 3)
+}
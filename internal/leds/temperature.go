@@ -0,0 +1,101 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/data"
+	"math"
+	"reflect"
+)
+
+// kelvinToPixel approximates the color of a black body at the passed
+// color temperature (in Kelvin).  See data.KelvinColor
+func kelvinToPixel(kelvin int) pixarray.Pixel {
+	c := data.KelvinColor(kelvin)
+	return pixarray.Pixel{R: c.R, G: c.G, B: c.B}
+}
+
+// metaColorType lets useWhiteChannel find the colors in meta info
+var metaColorType = reflect.TypeOf(data.MetaColor{})
+
+// useWhiteChannel returns a copy of the passed meta info with its color temperatures
+// (see data.MetaColor) moved onto the white channel, if the strip has one.  The white
+// part of the color comes from the white LED and the RGB LEDs add the tint
+func (sp StepProcessor) useWhiteChannel(meta any) any {
+	if sp.NumberOfColors < 4 || meta == nil {
+		return meta
+	}
+
+	v := reflect.New(reflect.TypeOf(meta)).Elem()
+	v.Set(reflect.ValueOf(meta))
+	whiten(v)
+
+	return v.Interface()
+}
+
+// whiten updates the colors in v.  Slices and pointers are copied first,
+// so the timeline's own meta info is never changed
+func whiten(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == metaColorType {
+			c := v.Interface().(data.MetaColor)
+			if c.K > 0 {
+				v.Set(reflect.ValueOf(whiteBalance(c)))
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if f := v.Field(i); f.CanSet() {
+				whiten(f)
+			}
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			c := reflect.New(v.Type().Elem())
+			c.Elem().Set(v.Elem())
+			whiten(c.Elem())
+			v.Set(c)
+		}
+	case reflect.Slice:
+		switch v.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Pointer, reflect.Slice:
+			if !v.IsNil() {
+				c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+				reflect.Copy(c, v)
+				for i := 0; i < c.Len(); i++ {
+					whiten(c.Index(i))
+				}
+				v.Set(c)
+			}
+		}
+	}
+}
+
+// whiteBalance moves the white part of a color onto the white channel
+func whiteBalance(c data.MetaColor) data.MetaColor {
+	w := c.R
+	if c.G < w {
+		w = c.G
+	}
+	if c.B < w {
+		w = c.B
+	}
+
+	return data.MetaColor{
+		R: c.R - w,
+		G: c.G - w,
+		B: c.B - w,
+		W: clampChannel(float64(c.W + w)),
+	}
+}
+
+// clampChannel rounds and clamps a color channel value to 0 - 255
+func clampChannel(f float64) int {
+	if f < 0 || math.IsNaN(f) {
+		return 0
+	}
+	if f > 255 {
+		return 255
+	}
+	return round(f)
+}
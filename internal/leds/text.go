@@ -0,0 +1,163 @@
+package leds
+
+import (
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/data/const/effect"
+	"strings"
+	"time"
+)
+
+// expandText fills in the template variables in the message
+func expandText(text string, now time.Time) string {
+	return strings.NewReplacer(
+		"{time}", now.Format("15:04"),
+		"{time12}", now.Format("3:04 PM"),
+		"{date}", now.Format("Jan 2"),
+		"{day}", now.Format("Monday"),
+		"{year}", now.Format("2006"),
+	).Replace(text)
+}
+
+// renderText draws the text into columns (indexed by [x][y]), with a blank column between characters
+func renderText(font bitmapFont, text string) [][]bool {
+	retval := [][]bool{}
+
+	for i, r := range []rune(text) {
+		if i > 0 {
+			retval = append(retval, make([]bool, font.height))
+		}
+
+		for x := 0; x < font.width; x++ {
+			column := make([]bool, font.height)
+			for y := 0; y < font.height; y++ {
+				column[y] = font.on(r, x, y)
+			}
+			retval = append(retval, column)
+		}
+	}
+
+	return retval
+}
+
+// drawText draws rendered text onto the matrix with its top left corner at x0/y0.
+// The color for each column of the text comes from the passed function
+func drawText(pa *pixarray.PixArray, m Matrix, columns [][]bool, x0, y0 int, color func(x int) pixarray.Pixel) {
+	for x, column := range columns {
+		sx := x0 + x
+		if sx < 0 || sx >= m.Width {
+			continue
+		}
+
+		c := color(x)
+		for y, lit := range column {
+			sy := y0 + y
+			if !lit || sy < 0 || sy >= m.Height {
+				continue
+			}
+
+			if i := m.Index(sx, sy); i < pa.NumPixels() {
+				pa.SetOne(i, c)
+			}
+		}
+	}
+}
+
+// ScrollingText scrolls a message across a matrix
+type ScrollingText struct {
+	text      string
+	font      bitmapFont
+	colors    palette
+	speed     float64
+	direction string
+	repeat    int
+	matrix    Matrix
+	columns   [][]bool // The rendered message, indexed by [x][y]
+	pass      int
+	passStart time.Time
+}
+
+// NewScrollingText creates a text effect.  Speed is in pixels per second, and repeat is
+// the number of times to scroll the message (0 means keep scrolling)
+func NewScrollingText(text string, font bitmapFont, colors palette, speed float64, direction string, repeat int, matrix Matrix) *ScrollingText {
+	st := ScrollingText{}
+	st.text = text
+	st.font = font
+	st.colors = colors
+	st.speed = speed
+	st.direction = strings.ToLower(direction)
+	st.repeat = repeat
+	st.matrix = matrix
+	return &st
+}
+
+// render draws the message into columns
+func (st *ScrollingText) render(now time.Time) {
+	st.columns = renderText(st.font, expandText(st.text, now))
+}
+
+// vertical returns true if the message scrolls up or down
+func (st *ScrollingText) vertical() bool {
+	return st.direction == effect.TEXT_UP || st.direction == effect.TEXT_DOWN
+}
+
+func (st *ScrollingText) Start(pa *pixarray.PixArray, now time.Time) {
+	st.pass = 0
+	st.passStart = now
+
+	//	Without a matrix, treat the strip as a single row
+	if !st.matrix.IsSet() {
+		st.matrix = Matrix{Width: pa.NumPixels(), Height: 1}
+	}
+
+	st.render(now)
+}
+
+func (st *ScrollingText) NextStep(pa *pixarray.PixArray, now time.Time) time.Duration {
+	textWidth := len(st.columns)
+
+	//	A pass is done once the message has completely scrolled off the matrix
+	passLength := textWidth + st.matrix.Width
+	if st.vertical() {
+		passLength = st.font.height + st.matrix.Height
+	}
+
+	offset := int(now.Sub(st.passStart).Seconds() * st.speed)
+	if offset >= passLength {
+		st.pass++
+		if st.repeat > 0 && st.pass >= st.repeat {
+			pa.SetAll(pixarray.Pixel{})
+			return 0
+		}
+
+		//	Render again, so things like the time stay current
+		st.passStart = now
+		st.render(now)
+		textWidth = len(st.columns)
+		offset = 0
+	}
+
+	//	Figure out where the top left corner of the message is
+	x0 := st.matrix.Width - offset
+	y0 := (st.matrix.Height - st.font.height) / 2
+	switch st.direction {
+	case effect.TEXT_RIGHT:
+		x0 = offset - textWidth
+	case effect.TEXT_UP:
+		x0 = (st.matrix.Width - textWidth) / 2
+		y0 = st.matrix.Height - offset
+	case effect.TEXT_DOWN:
+		x0 = (st.matrix.Width - textWidth) / 2
+		y0 = offset - st.font.height
+	}
+
+	pa.SetAll(pixarray.Pixel{})
+	drawText(pa, st.matrix, st.columns, x0, y0, func(x int) pixarray.Pixel {
+		return st.colors.colorAt(float64(x) / float64(textWidth))
+	})
+
+	return time.Millisecond
+}
+
+func (st *ScrollingText) Name() string {
+	return "TEXT"
+}
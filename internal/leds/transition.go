@@ -0,0 +1,207 @@
+package leds
+
+import (
+	"context"
+	"github.com/Jon-Bright/ledctl/pixarray"
+	"github.com/danesparza/fxpixel/internal/data"
+	"github.com/danesparza/fxpixel/internal/data/const/effect"
+	"github.com/danesparza/fxpixel/internal/data/const/step"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// wipeEdge is how much of the strip the soft edge of a wipe covers
+const wipeEdge = 0.1
+
+// TransitionStrip sits between the pixel array and the real strip.  Effects draw into
+// its frame buffer, and each write runs the step's filters over the frame.  While a
+// transition is running, each write blends the frame that was showing when the step
+// started into the new one
+type TransitionStrip struct {
+	pixarray.LEDStrip
+	mu         sync.Mutex
+	frame      []pixarray.Pixel // What the current step has drawn
+	filtered   []pixarray.Pixel // The frame once the filters have run over it
+	from       []pixarray.Pixel // What was showing when the transition started
+	shown      []pixarray.Pixel // What is showing on the strip right now
+	position   []float64        // Where each LED is along a wipe (0 - 1)
+	threshold  []float64        // When each LED switches over in a dissolve (0 - 1)
+	transition data.StepTransition
+	filters    *FilterChain
+	ease       easingFunc
+	start      time.Time
+	generation int
+}
+
+// NewTransitionStrip wraps the passed strip.  The matrix layout (if set) makes wipes run
+// across the matrix instead of along the wiring.  Showing is what the LEDs are showing
+// right now (if we know), so the first step can blend in from it
+func NewTransitionStrip(strip pixarray.LEDStrip, numPixels int, matrix Matrix, showing []pixarray.Pixel) *TransitionStrip {
+	t := TransitionStrip{
+		LEDStrip:  strip,
+		frame:     make([]pixarray.Pixel, numPixels),
+		filtered:  make([]pixarray.Pixel, numPixels),
+		from:      make([]pixarray.Pixel, numPixels),
+		shown:     make([]pixarray.Pixel, numPixels),
+		position:  make([]float64, numPixels),
+		threshold: make([]float64, numPixels),
+		ease:      easing(effect.EASE_LINEAR, effect.EASE_LINEAR),
+	}
+
+	copy(t.shown, showing)
+
+	for i := range t.position {
+		if numPixels > 1 {
+			t.position[i] = float64(i) / float64(numPixels-1)
+		}
+	}
+
+	if matrix.IsSet() && matrix.Width > 1 {
+		for y := 0; y < matrix.Height; y++ {
+			for x := 0; x < matrix.Width; x++ {
+				if i := matrix.Index(x, y); i < numPixels {
+					t.position[i] = float64(x) / float64(matrix.Width-1)
+				}
+			}
+		}
+	}
+
+	return &t
+}
+
+// Showing returns a copy of what the LEDs are showing right now
+func (t *TransitionStrip) Showing() []pixarray.Pixel {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	retval := make([]pixarray.Pixel, len(t.shown))
+	copy(retval, t.shown)
+	return retval
+}
+
+// GetPixel returns what the current step has drawn for the pixel
+func (t *TransitionStrip) GetPixel(i int) pixarray.Pixel {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.frame[i]
+}
+
+// SetPixel draws the pixel into the frame buffer
+func (t *TransitionStrip) SetPixel(i int, p pixarray.Pixel) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.frame[i] = p
+}
+
+// Write blends the frame buffer onto the strip and writes it
+func (t *TransitionStrip) Write() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.write(time.Now())
+}
+
+// write sends the (blended) frame to the strip.  The lock must be held
+func (t *TransitionStrip) write(now time.Time) error {
+	elapsed := 1.0
+	if t.transition.Duration > 0 {
+		elapsed = float64(now.Sub(t.start)) / float64(time.Duration(t.transition.Duration)*time.Millisecond)
+	}
+	progress := t.ease(elapsed)
+
+	frame := t.frame
+	if t.filters != nil {
+		copy(t.filtered, t.frame)
+		t.filters.apply(t.filtered, now)
+		frame = t.filtered
+	}
+
+	for i, p := range frame {
+		if elapsed < 1 {
+			p = mix(t.from[i], p, t.weight(i, progress))
+		}
+		t.shown[i] = p
+		t.LEDStrip.SetPixel(i, p)
+	}
+
+	return t.LEDStrip.Write()
+}
+
+// weight is how much of the new frame the pixel shows at the passed progress
+func (t *TransitionStrip) weight(i int, progress float64) float64 {
+	switch strings.ToLower(t.transition.Type) {
+	case step.TRANSITION_WIPE:
+		return clamp01((progress*(1+wipeEdge) - t.position[i]) / wipeEdge)
+	case step.TRANSITION_DISSOLVE:
+		if progress >= t.threshold[i] {
+			return 1
+		}
+		return 0
+	}
+
+	return progress
+}
+
+// Begin starts blending from whatever is showing into the frames drawn from now on.
+// The strip keeps refreshing itself until the transition is done, so effects that
+// only draw once still blend in.  Dissolves use rnd to pick the order pixels change in
+func (t *TransitionStrip) Begin(ctx context.Context, transition data.StepTransition, rnd *rand.Rand) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	copy(t.from, t.shown)
+	copy(t.frame, t.shown)
+	for i := range t.threshold {
+		t.threshold[i] = rnd.Float64()
+	}
+	t.transition = transition
+	t.ease = easing(transition.Easing, effect.EASE_IN_OUT)
+	t.start = time.Now()
+	t.generation++
+
+	go t.refresh(ctx, t.generation)
+}
+
+// SetFilters sets the filters that run over each frame from now on.  If the filters
+// change over time, the strip keeps refreshing itself so effects that only draw once
+// still animate
+func (t *TransitionStrip) SetFilters(ctx context.Context, filters *FilterChain) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.filters = filters
+	if filters != nil && filters.animated {
+		t.generation++
+		go t.refresh(ctx, t.generation)
+	}
+}
+
+// refresh rewrites the strip until the transition is done and the filters don't need
+// animating (or a new refresh starts)
+func (t *TransitionStrip) refresh(ctx context.Context, generation int) {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			t.mu.Lock()
+			if t.generation != generation {
+				t.mu.Unlock()
+				return
+			}
+			t.write(now)
+			done := now.Sub(t.start) >= time.Duration(t.transition.Duration)*time.Millisecond &&
+				(t.filters == nil || !t.filters.animated)
+			t.mu.Unlock()
+
+			if done {
+				return
+			}
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
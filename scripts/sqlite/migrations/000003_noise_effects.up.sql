@@ -0,0 +1,3 @@
+/* Noise based effect types */
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (9, 'plasma');
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (10, 'noise');
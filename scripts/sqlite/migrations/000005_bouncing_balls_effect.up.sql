@@ -0,0 +1,2 @@
+/* Bouncing balls effect type */
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (12, 'bouncing-balls');
@@ -0,0 +1,19 @@
+create table palette
+(
+    id      TEXT not null
+        constraint palette_pk
+            primary key,
+    name    TEXT not null
+        constraint palette_name_uk
+            unique,
+    created integer default CURRENT_TIMESTAMP,
+    stops   TEXT    /* JSON array of color stops */
+);
+
+/* Built in palettes */
+INSERT INTO palette (id, name, created, stops) VALUES ('rainbow', 'rainbow', '2024-06-01 00:00:00', '[{"position": 0, "color": {"R": 255}}, {"position": 0.17, "color": {"R": 255, "G": 255}}, {"position": 0.33, "color": {"G": 255}}, {"position": 0.5, "color": {"G": 255, "B": 255}}, {"position": 0.67, "color": {"B": 255}}, {"position": 0.83, "color": {"R": 255, "B": 255}}, {"position": 1, "color": {"R": 255}}]');
+INSERT INTO palette (id, name, created, stops) VALUES ('ocean', 'ocean', '2024-06-01 00:00:00', '[{"position": 0, "color": {"B": 64}}, {"position": 0.3, "color": {"G": 64, "B": 128}}, {"position": 0.6, "color": {"G": 160, "B": 160}}, {"position": 0.8, "color": {"R": 64, "G": 200, "B": 255}}, {"position": 1, "color": {"R": 200, "G": 255, "B": 255}}]');
+INSERT INTO palette (id, name, created, stops) VALUES ('lava', 'lava', '2024-06-01 00:00:00', '[{"position": 0, "color": {}}, {"position": 0.25, "color": {"R": 128}}, {"position": 0.5, "color": {"R": 255, "G": 40}}, {"position": 0.75, "color": {"R": 255, "G": 120}}, {"position": 1, "color": {"R": 255, "G": 200, "B": 40}}]');
+INSERT INTO palette (id, name, created, stops) VALUES ('forest', 'forest', '2024-06-01 00:00:00', '[{"position": 0, "color": {"G": 32}}, {"position": 0.3, "color": {"G": 96, "B": 16}}, {"position": 0.6, "color": {"R": 64, "G": 128}}, {"position": 0.8, "color": {"R": 32, "G": 160, "B": 64}}, {"position": 1, "color": {"R": 128, "G": 192, "B": 32}}]');
+INSERT INTO palette (id, name, created, stops) VALUES ('party', 'party', '2024-06-01 00:00:00', '[{"position": 0, "color": {"R": 85, "B": 171}}, {"position": 0.2, "color": {"R": 181, "B": 75}}, {"position": 0.4, "color": {"R": 232, "G": 23}}, {"position": 0.6, "color": {"R": 171, "G": 119}}, {"position": 0.8, "color": {"R": 242, "B": 14}}, {"position": 1, "color": {"G": 7, "B": 249}}]');
+INSERT INTO palette (id, name, created, stops) VALUES ('heat', 'heat', '2024-06-01 00:00:00', '[{"position": 0, "color": {}}, {"position": 0.33, "color": {"R": 255}}, {"position": 0.66, "color": {"R": 255, "G": 255}}, {"position": 1, "color": {"R": 255, "G": 255, "B": 255}}]');
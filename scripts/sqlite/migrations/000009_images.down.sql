@@ -0,0 +1,2 @@
+delete from timeline_step_effect_type where id = 15;
+drop table if exists image;
@@ -0,0 +1,16 @@
+create table image
+(
+    id        TEXT not null
+        constraint image_pk
+            primary key,
+    name      TEXT not null
+        constraint image_name_uk
+            unique,
+    created   integer default CURRENT_TIMESTAMP,
+    file_name TEXT not null, /* File name in the image store */
+    width     integer not null,
+    height    integer not null
+);
+
+/* Image effect type */
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (15, 'image');
@@ -0,0 +1 @@
+delete from timeline_step_effect_type where id = 16;
@@ -0,0 +1,2 @@
+/* Text effect type */
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (17, 'text');
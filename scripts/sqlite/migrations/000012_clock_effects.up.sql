@@ -0,0 +1,3 @@
+/* Clock and countdown effect types */
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (18, 'clock');
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (19, 'countdown');
@@ -0,0 +1,4 @@
+/* Audio reactive effect types */
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (20, 'vu-meter');
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (21, 'spectrum');
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (22, 'beat-pulse');
@@ -0,0 +1,2 @@
+/* Steps can blend in from the previous step */
+alter table timeline_step add column step_transition TEXT;
@@ -0,0 +1 @@
+alter table timeline_step drop column step_random;
@@ -0,0 +1,2 @@
+/* Step meta values can be picked at random */
+alter table timeline_step add column step_random TEXT;
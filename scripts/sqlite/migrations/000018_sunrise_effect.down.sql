@@ -0,0 +1 @@
+delete from timeline_step_effect_type where id in (25, 26);
@@ -0,0 +1,3 @@
+/* Sunrise and sunset effect types */
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (25, 'sunrise');
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (26, 'sunset');
@@ -0,0 +1,2 @@
+/* Circadian effect type */
+INSERT INTO timeline_step_effect_type (id, effect_type) VALUES (27, 'circadian');
@@ -0,0 +1,2 @@
+alter table timeline_step drop column step_filters;
+alter table timeline drop column filters;
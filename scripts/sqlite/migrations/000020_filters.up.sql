@@ -0,0 +1,3 @@
+/* Filters run over the frame after the effect draws it */
+alter table timeline add column filters TEXT;
+alter table timeline_step add column step_filters TEXT;
@@ -0,0 +1 @@
+delete from timeline_step_type where id = 6;
@@ -0,0 +1,2 @@
+/* Label steps name a place in the timeline that loops can go back to */
+INSERT INTO timeline_step_type (id, step_type) VALUES (6, 'label');
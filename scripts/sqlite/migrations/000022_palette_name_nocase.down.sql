@@ -0,0 +1 @@
+drop index if exists palette_name_nocase_uk;
@@ -0,0 +1,2 @@
+/* Effects look palettes up by name without caring about case, so names have to be unique that way too */
+create unique index palette_name_nocase_uk on palette (name collate nocase);
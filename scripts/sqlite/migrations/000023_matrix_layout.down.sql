@@ -0,0 +1,3 @@
+alter table system_config drop column matrix_serpentine;
+alter table system_config drop column matrix_height;
+alter table system_config drop column matrix_width;
@@ -0,0 +1,5 @@
+/* Matrix layout, for strips that are wired as a 2D grid (images, gifs, text and clocks use it).
+   A width of 0 means a plain strip */
+alter table system_config add column matrix_width integer not null default 0;
+alter table system_config add column matrix_height integer not null default 0;
+alter table system_config add column matrix_serpentine integer not null default 1;